@@ -0,0 +1,131 @@
+package main
+
+import (
+	"log"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// incidentClockPattern finds clock times such as "14:00" or "08.00" in an extracted incident time
+var incidentClockPattern = regexp.MustCompile(`(\d{1,2})[:.](\d{2})`)
+
+// pollState is what adaptive polling remembers about a URL between scheduled checks
+type pollState struct {
+	stableChecks int           // Consecutive checks that found the page unchanged
+	incidentTime string        // Time text of the last check that found the search terms (e.g. "До 14:00")
+	interval     time.Duration // Interval chosen after the previous check (for logging changes)
+}
+
+// observe updates the poll state with the result of a check
+func (p *pollState) observe(result URLCheckResult) {
+	if result.Unchanged && result.Error == nil {
+		p.stableChecks++
+	} else {
+		p.stableChecks = 0
+	}
+	if result.Found && result.Time != "" {
+		p.incidentTime = result.Time
+	}
+}
+
+// incidentClockTimes returns the clock times mentioned in an incident time text, on the day of now
+// Dates such as "15.10.2025" are skipped (a time is not directly next to another "." and digit)
+func incidentClockTimes(text string, now time.Time) []time.Time {
+	times := make([]time.Time, 0)
+	for _, loc := range incidentClockPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if start >= 2 && text[start-1] == '.' && isDigit(text[start-2]) {
+			continue
+		}
+		if start >= 1 && isDigit(text[start-1]) {
+			continue
+		}
+		if end+1 < len(text) && (text[end] == '.' || text[end] == ':') && isDigit(text[end+1]) {
+			continue
+		}
+		if end < len(text) && isDigit(text[end]) {
+			continue
+		}
+
+		hour, _ := strconv.Atoi(text[loc[2]:loc[3]])
+		minute, _ := strconv.Atoi(text[loc[4]:loc[5]])
+		if hour > 23 || minute > 59 {
+			continue
+		}
+		times = append(times, time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location()))
+	}
+	return times
+}
+
+// isDigit reports whether b is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// nearIncidentTime reports whether now is within window of a clock time in the incident text,
+// and whether any of its times is still ahead (not yet window past)
+func nearIncidentTime(text string, now time.Time, window time.Duration) (near, pending bool) {
+	for _, t := range incidentClockTimes(text, now) {
+		if now.After(t.Add(-window)) && now.Before(t.Add(window)) {
+			near = true
+		}
+		if now.Before(t.Add(window)) {
+			pending = true
+		}
+	}
+	return near, pending
+}
+
+// maxCheckInterval returns the longest wait between scheduled checks of a URL without a schedule
+func (m *Monitor) maxCheckInterval(settings URLSettings) time.Duration {
+	interval := time.Duration(settings.CheckIntervalSeconds) * time.Second
+	config := m.getConfig()
+	if config.AdaptivePolling && settings.schedule == nil {
+		interval = max(interval, time.Duration(config.AdaptiveMaxIntervalSeconds)*time.Second)
+	}
+	return interval
+}
+
+// adaptiveInterval returns how long to wait after a check: the fast interval while the search terms are
+// found or a time in the incident is near, otherwise check_interval_seconds doubled for each consecutive
+// unchanged check, up to adaptive_max_interval_seconds
+func (m *Monitor) adaptiveInterval(url string, settings URLSettings, state *pollState, now time.Time) time.Duration {
+	config := m.getConfig()
+	base := time.Duration(settings.CheckIntervalSeconds) * time.Second
+
+	m.mu.RLock()
+	found := m.foundURLs[url]
+	m.mu.RUnlock()
+
+	// Incident times are local times (time_offset_hours), like schedules
+	offset := time.Duration(config.TimeOffsetHours) * time.Hour
+	near, pending := nearIncidentTime(state.incidentTime, now.Add(offset), time.Duration(config.AdaptiveNearMinutes)*time.Minute)
+	if !found && !pending {
+		state.incidentTime = "" // The incident is over and its times have passed
+	}
+
+	var interval time.Duration
+	reason := "page changed"
+	switch {
+	case found || near:
+		interval = min(base, time.Duration(config.AdaptiveFastIntervalSeconds)*time.Second)
+		reason = "incident active"
+	default:
+		interval = base
+		limit := m.maxCheckInterval(settings)
+		for i := 0; i < state.stableChecks && interval < limit; i++ {
+			interval *= 2
+		}
+		interval = min(interval, limit)
+		if state.stableChecks > 0 {
+			reason = "page unchanged"
+		}
+	}
+
+	if interval != state.interval {
+		log.Printf("⏱️  Checking %s every %s (%s)", url, interval, reason)
+		state.interval = interval
+	}
+	return interval
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// errURLNotFound is returned when an edit targets a URL ID that is not monitored
+var errURLNotFound = errors.New("URL not found")
+
+// adminSavedMessages maps the ?saved= redirect parameter to its translation key
+var adminSavedMessages = map[string]string{
+	"added":   "ui.admin.saved.added",
+	"updated": "ui.admin.saved.updated",
+	"deleted": "ui.admin.saved.deleted",
+}
+
+// adminURLForm holds the values of one URL form on the admin page
+type adminURLForm struct {
+	ID          string
+	Name        string
+	URL         string
+	SearchTerms string // One term per line
+	Extractor   string
+
+	// Overrides; empty = use the global value
+	Schedule             string
+	CheckIntervalSeconds string
+	ConnectTimeout       string
+	AlertCooldownMinutes string
+	MaxEmailsPerDay      string
+	Recipients           string // One address per line
+	Proxy                string
+
+	// Request options
+	Headers          string // One "Name: value" per line
+	CookieJar        bool
+	CAFile           string
+	PinnedCertSHA256 string
+	TLSMinVersion    string
+}
+
+// urlConfigEqual reports whether two URL configs would be monitored the same way
+func urlConfigEqual(a, b URLConfig) bool {
+	return a.URL == b.URL && a.Name == b.Name && a.Extractor == b.Extractor && slices.Equal(a.SearchTerms, b.SearchTerms) &&
+		a.Schedule == b.Schedule && a.CheckIntervalSeconds == b.CheckIntervalSeconds && a.ConnectTimeout == b.ConnectTimeout &&
+		a.AlertCooldownMinutes == b.AlertCooldownMinutes && a.MaxEmailsPerDay == b.MaxEmailsPerDay &&
+		slices.Equal(a.Recipients, b.Recipients) && a.Proxy == b.Proxy &&
+		maps.Equal(a.Headers, b.Headers) && a.CookieJar == b.CookieJar && a.CAFile == b.CAFile &&
+		a.PinnedCertSHA256 == b.PinnedCertSHA256 && a.TLSMinVersion == b.TLSMinVersion
+}
+
+// formLines splits a textarea value into trimmed, non-empty lines
+func formLines(value string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// formHeaders parses "Name: value" lines into request headers (nil if there are none)
+func formHeaders(value string) (map[string]string, error) {
+	var headers map[string]string
+	for _, line := range formLines(value) {
+		name, headerValue, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("header %q must look like \"Name: value\"", line)
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
+
+// formatHeaders shows request headers as sorted "Name: value" lines
+func formatHeaders(headers map[string]string) string {
+	lines := make([]string, 0, len(headers))
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		lines = append(lines, name+": "+headers[name])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formOverride parses an optional numeric override (empty = 0, the global value)
+func formOverride(r *http.Request, field string) (int, error) {
+	value := strings.TrimSpace(r.FormValue(field))
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a whole number", field)
+	}
+	return n, nil
+}
+
+// urlConfigFromForm builds a URLConfig from a submitted admin form
+func urlConfigFromForm(r *http.Request) (URLConfig, error) {
+	extractor := r.FormValue("extractor")
+	if extractor == extractorAuto {
+		extractor = "" // Default; keep it out of config.json
+	}
+
+	urlConfig := URLConfig{
+		URL:         strings.TrimSpace(r.FormValue("url")),
+		Name:        strings.TrimSpace(r.FormValue("name")),
+		SearchTerms: formLines(r.FormValue("search_terms")),
+		Extractor:   extractor,
+		Schedule:    strings.TrimSpace(r.FormValue("schedule")),
+		Proxy:       strings.TrimSpace(r.FormValue("proxy")),
+
+		CookieJar:        r.FormValue("cookie_jar") == "on",
+		CAFile:           strings.TrimSpace(r.FormValue("ca_file")),
+		PinnedCertSHA256: strings.TrimSpace(r.FormValue("pinned_cert_sha256")),
+		TLSMinVersion:    r.FormValue("tls_min_version"),
+	}
+	if recipients := formLines(r.FormValue("recipients")); len(recipients) > 0 {
+		urlConfig.Recipients = recipients
+	}
+
+	overrides := []struct {
+		field  string
+		target *int
+	}{
+		{"check_interval_seconds", &urlConfig.CheckIntervalSeconds},
+		{"connect_timeout", &urlConfig.ConnectTimeout},
+		{"alert_cooldown_minutes", &urlConfig.AlertCooldownMinutes},
+		{"max_emails_per_day", &urlConfig.MaxEmailsPerDay},
+	}
+	errs := make([]string, 0)
+	headers, err := formHeaders(r.FormValue("headers"))
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	urlConfig.Headers = headers
+	for _, override := range overrides {
+		n, err := formOverride(r, override.field)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		*override.target = n
+	}
+	if len(errs) > 0 {
+		return urlConfig, errors.New(strings.Join(errs, "\n"))
+	}
+	return urlConfig, nil
+}
+
+// formatOverride shows an unset (0) override as an empty field
+func formatOverride(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// formFromURLConfig fills an admin form from a URLConfig
+func formFromURLConfig(urlConfig URLConfig) adminURLForm {
+	extractor := urlConfig.Extractor
+	if extractor == "" {
+		extractor = extractorAuto
+	}
+	return adminURLForm{
+		ID:          urlID(urlConfig.URL),
+		Name:        urlConfig.Name,
+		URL:         urlConfig.URL,
+		SearchTerms: strings.Join(urlConfig.SearchTerms, "\n"),
+		Extractor:   extractor,
+
+		Schedule:             urlConfig.Schedule,
+		CheckIntervalSeconds: formatOverride(urlConfig.CheckIntervalSeconds),
+		ConnectTimeout:       formatOverride(urlConfig.ConnectTimeout),
+		AlertCooldownMinutes: formatOverride(urlConfig.AlertCooldownMinutes),
+		MaxEmailsPerDay:      formatOverride(urlConfig.MaxEmailsPerDay),
+		Recipients:           strings.Join(urlConfig.Recipients, "\n"),
+		Proxy:                urlConfig.Proxy,
+
+		Headers:          formatHeaders(urlConfig.Headers),
+		CookieJar:        urlConfig.CookieJar,
+		CAFile:           urlConfig.CAFile,
+		PinnedCertSHA256: urlConfig.PinnedCertSHA256,
+		TLSMinVersion:    urlConfig.TLSMinVersion,
+	}
+}
+
+// applyURLConfigs edits the monitored URLs: the result is validated with ValidateConfig,
+// written back to the config file, and check loops are started/stopped to match
+func (m *Monitor) applyURLConfigs(edit func([]URLConfig) ([]URLConfig, error)) error {
+	m.configMu.Lock()
+	defer m.configMu.Unlock()
+
+	// An environment override would win over whatever is written to the file
+	if name := envName("url_configs"); os.Getenv(name) != "" {
+		return fmt.Errorf("url_configs is set by %s; edit the environment instead", name)
+	}
+
+	current := m.getURLConfigs()
+	updated, err := edit(slices.Clone(current))
+	if err != nil {
+		return err
+	}
+
+	candidate := *m.getConfig()
+	candidate.URLConfigs = updated
+	if err := ValidateConfig(candidate); err != nil {
+		return err
+	}
+	if err := saveURLConfigs(m.configPath, updated); err != nil {
+		return err
+	}
+	m.config.Store(&candidate)
+
+	// The watcher should not treat our own write as an external change
+	if hash, err := configFileHash(m.configPath); err == nil {
+		m.configHash = hash
+	}
+
+	m.syncURLMonitors(current, updated, false)
+
+	return nil
+}
+
+// renderAdminURLs renders the URL management page
+// editID/form carry a rejected submission back to its form; editID "" is the add form
+func (m *Monitor) renderAdminURLs(w http.ResponseWriter, r *http.Request, status int, editID string, form adminURLForm, formErr error) {
+	lang := m.uiLocale(w, r)
+
+	urlConfigs := m.getURLConfigs()
+	forms := make([]adminURLForm, len(urlConfigs))
+	for i, urlConfig := range urlConfigs {
+		forms[i] = formFromURLConfig(urlConfig)
+		if formErr != nil && forms[i].ID == editID {
+			forms[i] = form
+			forms[i].ID = editID
+		}
+	}
+
+	newForm := adminURLForm{Extractor: extractorAuto}
+	if formErr != nil && editID == "" {
+		newForm = form
+	}
+
+	data := struct {
+		Lang        string
+		URLs        []adminURLForm
+		New         adminURLForm
+		Extractors  []string
+		TLSVersions []string
+		Global      URLSettings // Shown as placeholders for the overrides
+		GlobalProxy string      // Global proxy with the password redacted
+		EditID      string
+		Error       string
+		Message     string
+	}{
+		Lang:        lang,
+		URLs:        forms,
+		New:         newForm,
+		Extractors:  extractors,
+		TLSVersions: tlsVersionNames,
+		Global:      m.getConfig().urlSettings(URLConfig{}),
+		GlobalProxy: redactProxyURL(m.getConfig().ProxyURL),
+		EditID:      editID,
+	}
+	if formErr != nil {
+		data.Error = formErr.Error()
+	}
+	if key, ok := adminSavedMessages[r.URL.Query().Get("saved")]; ok && formErr == nil {
+		data.Message = key
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := m.templates.ExecuteTemplate(w, "admin.html", data); err != nil {
+		log.Printf("⚠️  Template error: %v", err)
+	}
+}
+
+// handleAdminURLs lists monitored URLs (GET) and adds a new one (POST)
+func (m *Monitor) handleAdminURLs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		m.renderAdminURLs(w, r, http.StatusOK, "", adminURLForm{}, nil)
+	case http.MethodPost:
+		urlConfig, err := urlConfigFromForm(r)
+		if err == nil {
+			err = m.applyURLConfigs(func(urlConfigs []URLConfig) ([]URLConfig, error) {
+				return append(urlConfigs, urlConfig), nil
+			})
+		}
+		if err != nil {
+			m.renderAdminURLs(w, r, http.StatusUnprocessableEntity, "", formFromURLConfig(urlConfig), err)
+			return
+		}
+		http.Redirect(w, r, "/admin/urls?saved=added", http.StatusSeeOther)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminURLUpdate replaces the settings of one URL
+func (m *Monitor) handleAdminURLUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	urlConfig, err := urlConfigFromForm(r)
+	if err == nil {
+		err = m.applyURLConfigs(func(urlConfigs []URLConfig) ([]URLConfig, error) {
+			for i := range urlConfigs {
+				if urlID(urlConfigs[i].URL) == id {
+					urlConfigs[i] = urlConfig
+					return urlConfigs, nil
+				}
+			}
+			return nil, errURLNotFound
+		})
+	}
+	if errors.Is(err, errURLNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		m.renderAdminURLs(w, r, http.StatusUnprocessableEntity, id, formFromURLConfig(urlConfig), err)
+		return
+	}
+	http.Redirect(w, r, "/admin/urls?saved=updated", http.StatusSeeOther)
+}
+
+// handleAdminURLDelete stops monitoring one URL
+func (m *Monitor) handleAdminURLDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	err := m.applyURLConfigs(func(urlConfigs []URLConfig) ([]URLConfig, error) {
+		for i := range urlConfigs {
+			if urlID(urlConfigs[i].URL) == id {
+				return slices.Delete(urlConfigs, i, i+1), nil
+			}
+		}
+		return nil, errURLNotFound
+	})
+	if errors.Is(err, errURLNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		urlConfig, _ := m.findURLConfig(id)
+		m.renderAdminURLs(w, r, http.StatusUnprocessableEntity, id, formFromURLConfig(urlConfig), err)
+		return
+	}
+	http.Redirect(w, r, "/admin/urls?saved=deleted", http.StatusSeeOther)
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// API response types (v1)
+// These are kept separate from the internal types so the JSON contract stays stable
+
+// apiURLStatus is a monitored URL in /api/v1/urls
+type apiURLStatus struct {
+	ID          string         `json:"id"`
+	URL         string         `json:"url"`
+	Name        string         `json:"name"`
+	SearchTerms []string       `json:"search_terms"`
+	Found       bool           `json:"found"`
+	Unreachable bool           `json:"unreachable"`
+	DownSince   *time.Time     `json:"down_since,omitempty"`
+	LastCheck   *time.Time     `json:"last_check,omitempty"`
+	NextCheck   *time.Time     `json:"next_check,omitempty"`
+	Checking    bool           `json:"checking"`
+	Failures    int            `json:"consecutive_failures"`
+	Flapping    bool           `json:"flapping"`
+	SoftError   string         `json:"soft_error,omitempty"`      // "maintenance", "portal" or "empty" while the site serves no real page
+	Throttled   *time.Time     `json:"throttled_until,omitempty"` // Checks are paused until then because the site asked to wait (Retry-After)
+	Settings    apiURLSettings `json:"settings"`
+}
+
+// apiURLSettings are the settings in effect for a URL in /api/v1/urls
+type apiURLSettings struct {
+	CheckIntervalSeconds int      `json:"check_interval_seconds"`
+	Schedule             string   `json:"schedule,omitempty"` // Replaces the interval when set
+	Proxy                string   `json:"proxy,omitempty"`    // Password redacted; "direct" bypasses the global proxy
+	ConnectTimeout       int      `json:"connect_timeout"`
+	AlertCooldownMinutes int      `json:"alert_cooldown_minutes"`
+	MaxEmailsPerDay      int      `json:"max_emails_per_day"`
+	Recipients           []string `json:"recipients"`
+	Overridden           []string `json:"overridden"` // Settings set for this URL rather than globally
+}
+
+// apiIncident is an event in /api/v1/incidents
+type apiIncident struct {
+	URL         string    `json:"url"`
+	Timestamp   time.Time `json:"timestamp"`
+	EventType   string    `json:"event_type"`
+	SearchTerms []string  `json:"search_terms,omitempty"`
+	Description string    `json:"description"`
+	Resolved    bool      `json:"resolved"`
+}
+
+// apiNotification is a sent email in /api/v1/notifications
+type apiNotification struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Type        string    `json:"type"`
+	URL         string    `json:"url"`
+	URLName     string    `json:"url_name"`
+	Subject     string    `json:"subject"`
+	Recipients  []string  `json:"recipients"`  // Configured recipients
+	Subscribers int       `json:"subscribers"` // Number of self-service subscribers reached
+}
+
+// apiCheckResult is the outcome of a "check now" request for one URL
+type apiCheckResult struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Status string `json:"status"` // "scheduled", "in_progress", "paused" or "busy"
+}
+
+// apiError is the body of every non-2xx API response
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// timePtr returns nil for zero times so they are omitted from JSON
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("⚠️  Failed to write JSON response: %v", err)
+	}
+}
+
+// writeJSONError writes an API error response
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, apiError{Error: message})
+}
+
+// requireGET rejects non-GET API requests, returning false if the request was handled
+func requireGET(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return false
+	}
+	return true
+}
+
+// requirePOST rejects non-POST API requests, returning false if the request was handled
+func requirePOST(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return false
+	}
+	return true
+}
+
+// checkStatusFromError maps a triggerCheck error to an apiCheckResult status
+func checkStatusFromError(err error) string {
+	switch err {
+	case nil:
+		return "scheduled"
+	case errCheckInProgress:
+		return "in_progress"
+	default:
+		if errors.As(err, &checkPausedError{}) {
+			return "paused"
+		}
+		return "busy"
+	}
+}
+
+// handleAPICheckURL schedules an immediate check of one URL
+func (m *Monitor) handleAPICheckURL(w http.ResponseWriter, r *http.Request) {
+	if !requirePOST(w, r) {
+		return
+	}
+
+	urlConfig, exists := m.findURLConfig(r.PathValue("id"))
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, "unknown URL id")
+		return
+	}
+
+	err := m.triggerCheck(urlConfig)
+	switch err {
+	case nil:
+		writeJSON(w, http.StatusAccepted, apiCheckResult{ID: urlID(urlConfig.URL), URL: urlConfig.URL, Status: "scheduled"})
+	case errCheckInProgress:
+		writeJSONError(w, http.StatusConflict, err.Error())
+	default:
+		var paused checkPausedError
+		if errors.As(err, &paused) {
+			w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(time.Until(paused.until).Seconds())))))
+			writeJSONError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+	}
+}
+
+// handleAPICheckAll schedules an immediate check of every URL
+func (m *Monitor) handleAPICheckAll(w http.ResponseWriter, r *http.Request) {
+	if !requirePOST(w, r) {
+		return
+	}
+
+	urlConfigs := m.getURLConfigs()
+	results := make([]apiCheckResult, len(urlConfigs))
+	for i, urlConfig := range urlConfigs {
+		results[i] = apiCheckResult{
+			ID:     urlID(urlConfig.URL),
+			URL:    urlConfig.URL,
+			Status: checkStatusFromError(m.triggerCheck(urlConfig)),
+		}
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"checks": results,
+	})
+}
+
+// overriddenSettings lists the settings a URL overrides, sorted by name
+func overriddenSettings(settings URLSettings) []string {
+	names := make([]string, 0, len(settings.Overridden))
+	for name := range settings.Overridden {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// handleAPIURLs returns the current status of every monitored URL
+func (m *Monitor) handleAPIURLs(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	statuses := m.getURLStatuses()
+	urls := make([]apiURLStatus, len(statuses))
+	for i, status := range statuses {
+		urls[i] = apiURLStatus{
+			ID:          status.ID,
+			URL:         status.URL,
+			Name:        status.Name,
+			SearchTerms: status.SearchTerms,
+			Found:       status.Found,
+			Unreachable: status.Unreachable,
+			DownSince:   timePtr(status.DownSince),
+			LastCheck:   timePtr(status.LastCheck),
+			NextCheck:   timePtr(status.NextCheck),
+			Checking:    status.Checking,
+			Failures:    status.Failures,
+			Flapping:    status.Flapping,
+			SoftError:   status.SoftError,
+			Throttled:   timePtr(status.Throttled),
+			Settings: apiURLSettings{
+				CheckIntervalSeconds: status.Settings.CheckIntervalSeconds,
+				Schedule:             status.Settings.Schedule,
+				ConnectTimeout:       status.Settings.ConnectTimeout,
+				AlertCooldownMinutes: status.Settings.AlertCooldownMinutes,
+				MaxEmailsPerDay:      status.Settings.MaxEmailsPerDay,
+				Recipients:           status.Settings.Recipients,
+				Proxy:                redactProxyURL(status.Settings.Proxy),
+				Overridden:           overriddenSettings(status.Settings),
+			},
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"urls": urls,
+	})
+}
+
+// handleAPIIncidents returns found/not-found events within the recent matches window (newest first)
+func (m *Monitor) handleAPIIncidents(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	events := m.getRecentEvents()
+	incidents := make([]apiIncident, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		incidents = append(incidents, apiIncident{
+			URL:         event.URL,
+			Timestamp:   event.Timestamp,
+			EventType:   event.EventType,
+			SearchTerms: event.SearchTerms,
+			Description: event.Message,
+			Resolved:    event.EventType == "not_found",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"window_hours": m.getConfig().RecentMatchesHours,
+		"incidents":    incidents,
+	})
+}
+
+// handleAPINotifications returns recently sent emails (newest first)
+// Query parameter "limit" (1-100, default 20) controls how many are returned
+func (m *Monitor) handleAPINotifications(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be an integer between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	sent := m.getRecentEmailNotifications(limit)
+	notifications := make([]apiNotification, len(sent))
+	for i, notification := range sent {
+		notifications[i] = apiNotification{
+			Timestamp:   notification.Timestamp,
+			Type:        notification.Type,
+			URL:         notification.URL,
+			URLName:     notification.URLName,
+			Subject:     notification.Subject,
+			Recipients:  notification.Recipients,
+			Subscribers: notification.Subscribers,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"notifications": notifications,
+	})
+}
+
+// handleAPIStateStats returns persistent state statistics
+func (m *Monitor) handleAPIStateStats(w http.ResponseWriter, r *http.Request) {
+	if !requireGET(w, r) {
+		return
+	}
+
+	if m.state == nil {
+		writeJSONError(w, http.StatusServiceUnavailable, "state not initialized")
+		return
+	}
+
+	stats := m.state.GetStats()
+	stats["uptime_seconds"] = int(time.Since(m.statsStartTime).Seconds())
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handleAPINotFound handles unknown /api/ paths
+func (m *Monitor) handleAPINotFound(w http.ResponseWriter, r *http.Request) {
+	writeJSONError(w, http.StatusNotFound, "not found")
+}
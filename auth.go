@@ -256,7 +256,7 @@ func (m *Monitor) validateReturnURL(returnURL string) string {
 func (m *Monitor) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Skip if auth is disabled
-		if !m.config.AuthEnabled {
+		if !m.getConfig().AuthEnabled {
 			next(w, r)
 			return
 		}
@@ -264,6 +264,12 @@ func (m *Monitor) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		// Check for session cookie (service-specific name)
 		cookie, err := r.Cookie("nestanak_session")
 		if err != nil || !m.sessionManager.ValidateSession(cookie.Value) {
+			// API clients get a JSON error instead of a redirect to the login page
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeJSONError(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+
 			// Redirect to login with return URL (validated)
 			returnURL := r.URL.Path
 			if r.URL.RawQuery != "" {
@@ -297,9 +303,11 @@ func (m *Monitor) handleLogin(w http.ResponseWriter, r *http.Request) {
 		safeReturnURL := m.validateReturnURL(returnURL)
 
 		data := struct {
+			Lang      string
 			Error     string
 			ReturnURL string
 		}{
+			Lang:      m.uiLocale(w, r),
 			ReturnURL: safeReturnURL,
 		}
 
@@ -319,11 +327,14 @@ func (m *Monitor) handleLogin(w http.ResponseWriter, r *http.Request) {
 			// Validate return URL
 			safeReturnURL := m.validateReturnURL(r.FormValue("return"))
 
+			lang := m.uiLocale(w, r)
 			data := struct {
+				Lang      string
 				Error     string
 				ReturnURL string
 			}{
-				Error:     "Too many failed attempts. Please try again later.",
+				Lang:      lang,
+				Error:     translate(lang, "ui.login.locked_out"),
 				ReturnURL: safeReturnURL,
 			}
 			w.WriteHeader(http.StatusTooManyRequests)
@@ -333,7 +344,7 @@ func (m *Monitor) handleLogin(w http.ResponseWriter, r *http.Request) {
 
 		// Verify password
 		password := r.FormValue("password")
-		valid, err := VerifyArgon2Hash(password, m.config.PasswordHash)
+		valid, err := VerifyArgon2Hash(password, m.getConfig().PasswordHash)
 		if err != nil || !valid {
 			m.sessionManager.RecordFailedLogin(ip)
 			log.Printf("⚠️  Failed login attempt from %s", ip)
@@ -341,11 +352,14 @@ func (m *Monitor) handleLogin(w http.ResponseWriter, r *http.Request) {
 			// Validate return URL
 			safeReturnURL := m.validateReturnURL(r.FormValue("return"))
 
+			lang := m.uiLocale(w, r)
 			data := struct {
+				Lang      string
 				Error     string
 				ReturnURL string
 			}{
-				Error:     "Invalid password",
+				Lang:      lang,
+				Error:     translate(lang, "ui.login.invalid"),
 				ReturnURL: safeReturnURL,
 			}
 			w.WriteHeader(http.StatusUnauthorized)
@@ -369,7 +383,7 @@ func (m *Monitor) handleLogin(w http.ResponseWriter, r *http.Request) {
 			Name:     "nestanak_session",
 			Value:    token,
 			Path:     "/",
-			MaxAge:   m.config.SessionTimeoutMinutes * 60,
+			MaxAge:   m.getConfig().SessionTimeoutMinutes * 60,
 			HttpOnly: true,
 			SameSite: http.SameSiteStrictMode,
 			// Domain not set = cookie is isolated to this exact host:port
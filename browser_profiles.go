@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Browser families a User-Agent is sent with headers of
+const (
+	familyChrome  = "chrome"  // Chromium-based: Chrome, Edge, Opera (client hints)
+	familyFirefox = "firefox" // Gecko
+	familySafari  = "safari"  // WebKit, including Chrome and Firefox on iOS
+	familyOther   = "other"
+)
+
+// browserAcceptEncoding is what every profile advertises: browsers also offer br and zstd over HTTPS,
+// but only gzip and deflate can be decoded with the standard library (see decodeBody)
+const browserAcceptEncoding = "gzip, deflate"
+
+// browserHeaders are the Accept and Accept-Language headers each family sends for a page navigation
+// (the languages are the same; the q-values follow each browser's own format)
+var browserHeaders = map[string]struct{ accept, acceptLanguage string }{
+	familyChrome: {
+		accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7",
+		acceptLanguage: "sr-RS,sr;q=0.9,en-US;q=0.8,en;q=0.7",
+	},
+	familyFirefox: {
+		accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		acceptLanguage: "sr-RS,sr;q=0.8,en-US;q=0.5,en;q=0.3",
+	},
+	familySafari: {
+		accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		acceptLanguage: "sr-RS,sr;q=0.9,en;q=0.8",
+	},
+	familyOther: {
+		accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		acceptLanguage: "sr-RS,sr;q=0.9,en;q=0.8",
+	},
+}
+
+// chromiumBrandPattern finds the branded Chromium browsers and their major versions in a User-Agent
+var chromiumBrandPattern = regexp.MustCompile(`(Chrome|Edg|OPR)/(\d+)`)
+
+// browserFamily returns the family a User-Agent belongs to
+func browserFamily(userAgent string) string {
+	switch {
+	// Every browser on iOS is WebKit and sends Safari's headers
+	case strings.Contains(userAgent, "CriOS/") || strings.Contains(userAgent, "FxiOS/") || strings.Contains(userAgent, "EdgiOS/"):
+		return familySafari
+	case strings.Contains(userAgent, "Firefox/"):
+		return familyFirefox
+	case strings.Contains(userAgent, "Chrome/"):
+		return familyChrome
+	case strings.Contains(userAgent, "Safari/"):
+		return familySafari
+	}
+	return familyOther
+}
+
+// chromiumPlatform returns the Sec-CH-UA-Platform value for a Chromium User-Agent
+func chromiumPlatform(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "Android"):
+		return "Android"
+	case strings.Contains(userAgent, "Windows"):
+		return "Windows"
+	case strings.Contains(userAgent, "Macintosh"):
+		return "macOS"
+	case strings.Contains(userAgent, "CrOS"):
+		return "Chrome OS"
+	case strings.Contains(userAgent, "Linux"):
+		return "Linux"
+	}
+	return "Unknown"
+}
+
+// chromiumBrands builds the Sec-CH-UA brand list of a Chromium User-Agent the way Chromium does:
+// the browser brand, "Chromium" and a GREASE brand, ordered and named by the major version
+func chromiumBrands(userAgent string) string {
+	brand, version, chromium := "Google Chrome", "", ""
+	for _, match := range chromiumBrandPattern.FindAllStringSubmatch(userAgent, -1) {
+		switch match[1] {
+		case "Chrome":
+			chromium = match[2]
+			if version == "" {
+				version = match[2]
+			}
+		case "Edg":
+			brand, version = "Microsoft Edge", match[2]
+		case "OPR":
+			brand, version = "Opera", match[2]
+		}
+	}
+
+	major, _ := strconv.Atoi(chromium)
+	greaseChars := []string{" ", "(", ":", "-", ".", "/", ")", ";", "=", "?", "_"}
+	greaseVersions := []string{"8", "99", "24"}
+	orders := [][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+
+	order := orders[major%len(orders)]
+	brands := make([]string, 3)
+	brands[order[0]] = fmt.Sprintf(`"Not%sA%sBrand";v="%s"`, greaseChars[major%len(greaseChars)], greaseChars[(major+1)%len(greaseChars)], greaseVersions[major%len(greaseVersions)])
+	brands[order[1]] = fmt.Sprintf(`"Chromium";v="%s"`, chromium)
+	brands[order[2]] = fmt.Sprintf(`"%s";v="%s"`, brand, version)
+	return strings.Join(brands, ", ")
+}
+
+// setBrowserHeaders sets the User-Agent and the headers its browser sends with it when opening a page,
+// so a request does not pair a browser User-Agent with Go's default headers
+func setBrowserHeaders(req *http.Request, userAgent string) {
+	family := browserFamily(userAgent)
+	headers := browserHeaders[family]
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", headers.accept)
+	req.Header.Set("Accept-Language", headers.acceptLanguage)
+	req.Header.Set("Accept-Encoding", browserAcceptEncoding)
+	if family == familyOther {
+		return
+	}
+
+	if family == familyChrome {
+		mobile := "?0"
+		if strings.Contains(userAgent, "Mobile") {
+			mobile = "?1"
+		}
+		req.Header.Set("Sec-CH-UA", chromiumBrands(userAgent))
+		req.Header.Set("Sec-CH-UA-Mobile", mobile)
+		req.Header.Set("Sec-CH-UA-Platform", `"`+chromiumPlatform(userAgent)+`"`)
+	}
+	req.Header.Set("Upgrade-Insecure-Requests", "1")
+	req.Header.Set("Sec-Fetch-Dest", "document")
+	req.Header.Set("Sec-Fetch-Mode", "navigate")
+	req.Header.Set("Sec-Fetch-Site", "none")
+	req.Header.Set("Sec-Fetch-User", "?1")
+}
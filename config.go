@@ -1,69 +1,413 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
 // URLConfig represents a URL to monitor with its search terms
 type URLConfig struct {
-	URL         string   `json:"url"`
-	SearchTerms []string `json:"search_terms"`
-	Name        string   `json:"name"` // Optional friendly name for the URL
+	URL         string   `json:"url" yaml:"url" toml:"url"`
+	Name        string   `json:"name" yaml:"name" toml:"name"` // Optional friendly name for the URL
+	SearchTerms []string `json:"search_terms" yaml:"search_terms" toml:"search_terms"`
+	Extractor   string   `json:"extractor,omitempty" yaml:"extractor,omitempty" toml:"extractor,omitempty"` // "auto" (default), "power" or "water"
+
+	// Optional overrides of the global settings (0 or empty = use the global value)
+	Schedule             string   `json:"schedule,omitempty" yaml:"schedule,omitempty" toml:"schedule,omitempty"` // Cron expression or time-of-day windows, replaces the check interval
+	CheckIntervalSeconds int      `json:"check_interval_seconds,omitempty" yaml:"check_interval_seconds,omitempty" toml:"check_interval_seconds,omitempty"`
+	ConnectTimeout       int      `json:"connect_timeout,omitempty" yaml:"connect_timeout,omitempty" toml:"connect_timeout,omitempty"`
+	AlertCooldownMinutes int      `json:"alert_cooldown_minutes,omitempty" yaml:"alert_cooldown_minutes,omitempty" toml:"alert_cooldown_minutes,omitempty"`
+	MaxEmailsPerDay      int      `json:"max_emails_per_day,omitempty" yaml:"max_emails_per_day,omitempty" toml:"max_emails_per_day,omitempty"` // Overrides max_emails_per_url_per_day
+	Recipients           []string `json:"recipients,omitempty" yaml:"recipients,omitempty" toml:"recipients,omitempty"`                         // Replaces the global recipients for match alerts
+	Proxy                string   `json:"proxy,omitempty" yaml:"proxy,omitempty" toml:"proxy,omitempty"`                                        // Proxy URL, or "direct" to bypass the global proxy
+
+	// Optional request options
+	Headers          map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" toml:"headers,omitempty"`                                  // Extra request headers (e.g. Accept-Language, Referer, Cookie)
+	CookieJar        bool              `json:"cookie_jar,omitempty" yaml:"cookie_jar,omitempty" toml:"cookie_jar,omitempty"`                         // Keep cookies set by the site between checks
+	CAFile           string            `json:"ca_file,omitempty" yaml:"ca_file,omitempty" toml:"ca_file,omitempty"`                                  // PEM bundle trusted in addition to the system CAs
+	PinnedCertSHA256 string            `json:"pinned_cert_sha256,omitempty" yaml:"pinned_cert_sha256,omitempty" toml:"pinned_cert_sha256,omitempty"` // Accept only this server certificate (replaces chain verification)
+	TLSMinVersion    string            `json:"tls_min_version,omitempty" yaml:"tls_min_version,omitempty" toml:"tls_min_version,omitempty"`          // "1.0" to "1.3" (default: Go's minimum, 1.2)
+}
+
+// Extractors for date/time/address details of a match
+const (
+	extractorAuto  = "auto"  // Chosen from the URL: bvk.rs pages are water, everything else power
+	extractorPower = "power" // Elektrodistribucija tables (extractDate/extractTime/extractAddress)
+	extractorWater = "water" // BVK text pages (extractDateWater/extractTimeWater/extractAddressWater)
+)
+
+// extractors lists the valid URLConfig.Extractor values
+var extractors = []string{extractorAuto, extractorPower, extractorWater}
+
+// extractorType resolves the extractor to use for this URL
+func (u URLConfig) extractorType() string {
+	switch u.Extractor {
+	case extractorPower, extractorWater:
+		return u.Extractor
+	}
+	if strings.Contains(u.URL, "bvk.rs") {
+		return extractorWater
+	}
+	return extractorPower
+}
+
+// urlSettings resolves the settings in effect for a URL: its own override, or the global value
+func (c *Config) urlSettings(u URLConfig) URLSettings {
+	settings := URLSettings{
+		CheckIntervalSeconds: c.CheckIntervalSeconds,
+		ConnectTimeout:       c.ConnectTimeout,
+		AlertCooldownMinutes: c.AlertCooldownMinutes,
+		MaxEmailsPerDay:      c.MaxEmailsPerURLPerDay,
+		Recipients:           c.Recipients,
+		Proxy:                c.ProxyURL,
+		Overridden:           make(map[string]bool),
+	}
+	if u.CheckIntervalSeconds > 0 {
+		settings.CheckIntervalSeconds = u.CheckIntervalSeconds
+		settings.Overridden["check_interval_seconds"] = true
+	}
+	if u.Schedule != "" {
+		if schedule, err := parseSchedule(u.Schedule); err == nil {
+			settings.Schedule = u.Schedule
+			settings.schedule = schedule
+			settings.Overridden["schedule"] = true
+		}
+	}
+	if u.ConnectTimeout > 0 {
+		settings.ConnectTimeout = u.ConnectTimeout
+		settings.Overridden["connect_timeout"] = true
+	}
+	if u.AlertCooldownMinutes > 0 {
+		settings.AlertCooldownMinutes = u.AlertCooldownMinutes
+		settings.Overridden["alert_cooldown_minutes"] = true
+	}
+	if u.MaxEmailsPerDay > 0 {
+		settings.MaxEmailsPerDay = u.MaxEmailsPerDay
+		settings.Overridden["max_emails_per_day"] = true
+	}
+	if len(u.Recipients) > 0 {
+		settings.Recipients = u.Recipients
+		settings.Overridden["recipients"] = true
+	}
+	if u.Proxy != "" {
+		settings.Proxy = u.Proxy
+		settings.Overridden["proxy"] = true
+	}
+	return settings
 }
 
 // Config represents the configuration structure
 type Config struct {
-	CheckIntervalSeconds    int         `json:"check_interval_seconds"`
-	AlertCooldownMinutes    int         `json:"alert_cooldown_minutes"`
-	EmailRateLimitPerHour   int         `json:"email_rate_limit_per_hour"`
-	MaxEmailsPerURLPerDay   int         `json:"max_emails_per_url_per_day"`
-	MaxConcurrentChecks     int         `json:"max_concurrent_checks"`
-	ConnectTimeout          int         `json:"connect_timeout"`
-	TimeOffsetHours         int         `json:"time_offset_hours"`
-	DNSCacheTTLMinutes      int         `json:"dns_cache_ttl_minutes"`
-	UserAgentRotation       bool        `json:"user_agent_rotation_enabled"`
-	UserAgentPoolSize       int         `json:"user_agent_pool_size"`
-	HTTPEnabled             bool        `json:"http_enabled"`
-	HTTPListen              string      `json:"http_listen"`
-	HTTPLogLines            int         `json:"http_log_lines"`
-	HTTPRateLimitPerMinute  int         `json:"http_rate_limit_per_minute"`
-	LogBufferFlushSeconds   int         `json:"log_buffer_flush_seconds"`
-	RecentMatchesHours      int         `json:"recent_matches_hours"`
-	RecentEventsBufferSize  int         `json:"recent_events_buffer_size"`
-	AuthEnabled             bool        `json:"auth_enabled"`
-	PasswordHash            string      `json:"password_hash"`
-	Argon2Memory            uint32      `json:"argon2_memory"`
-	Argon2Time              uint32      `json:"argon2_time"`
-	Argon2Threads           uint8       `json:"argon2_threads"`
-	SessionTimeoutMinutes   int         `json:"session_timeout_minutes"`
-	MaxLoginAttempts        int         `json:"max_login_attempts"`
-	LockoutDurationMinutes  int         `json:"lockout_duration_minutes"`
-	URLConfigs              []URLConfig `json:"url_configs"`
-	Recipients              []string    `json:"recipients"`
-	ErrorRecipient          string      `json:"error_recipient"`
-	BrevoAPIKey             string      `json:"brevo_api_key"`
-	SenderEmail             string      `json:"sender_email"`
-	SenderName              string      `json:"sender_name"`
-	StateFilePath           string      `json:"state_file_path"` // Path to persist state across restarts
+	CheckIntervalSeconds   int         `json:"check_interval_seconds" yaml:"check_interval_seconds" toml:"check_interval_seconds"`
+	AlertCooldownMinutes   int         `json:"alert_cooldown_minutes" yaml:"alert_cooldown_minutes" toml:"alert_cooldown_minutes"`
+	EmailRateLimitPerHour  int         `json:"email_rate_limit_per_hour" yaml:"email_rate_limit_per_hour" toml:"email_rate_limit_per_hour"`
+	MaxEmailsPerURLPerDay  int         `json:"max_emails_per_url_per_day" yaml:"max_emails_per_url_per_day" toml:"max_emails_per_url_per_day"`
+	MaxConcurrentChecks    int         `json:"max_concurrent_checks" yaml:"max_concurrent_checks" toml:"max_concurrent_checks"`
+	ConnectTimeout         int         `json:"connect_timeout" yaml:"connect_timeout" toml:"connect_timeout"`
+	TimeOffsetHours        int         `json:"time_offset_hours" yaml:"time_offset_hours" toml:"time_offset_hours"`
+	DNSCacheTTLMinutes     int         `json:"dns_cache_ttl_minutes" yaml:"dns_cache_ttl_minutes" toml:"dns_cache_ttl_minutes"`
+	UserAgentRotation      bool        `json:"user_agent_rotation_enabled" yaml:"user_agent_rotation_enabled" toml:"user_agent_rotation_enabled"`
+	UserAgentPoolSize      int         `json:"user_agent_pool_size" yaml:"user_agent_pool_size" toml:"user_agent_pool_size"`
+	HTTPEnabled            bool        `json:"http_enabled" yaml:"http_enabled" toml:"http_enabled"`
+	HTTPListen             string      `json:"http_listen" yaml:"http_listen" toml:"http_listen"`
+	HTTPLogLines           int         `json:"http_log_lines" yaml:"http_log_lines" toml:"http_log_lines"`
+	HTTPRateLimitPerMinute int         `json:"http_rate_limit_per_minute" yaml:"http_rate_limit_per_minute" toml:"http_rate_limit_per_minute"`
+	LogBufferFlushSeconds  int         `json:"log_buffer_flush_seconds" yaml:"log_buffer_flush_seconds" toml:"log_buffer_flush_seconds"`
+	RecentMatchesHours     int         `json:"recent_matches_hours" yaml:"recent_matches_hours" toml:"recent_matches_hours"`
+	RecentEventsBufferSize int         `json:"recent_events_buffer_size" yaml:"recent_events_buffer_size" toml:"recent_events_buffer_size"`
+	AuthEnabled            bool        `json:"auth_enabled" yaml:"auth_enabled" toml:"auth_enabled"`
+	PasswordHash           string      `json:"password_hash" yaml:"password_hash" toml:"password_hash"`
+	Argon2Memory           uint32      `json:"argon2_memory" yaml:"argon2_memory" toml:"argon2_memory"`
+	Argon2Time             uint32      `json:"argon2_time" yaml:"argon2_time" toml:"argon2_time"`
+	Argon2Threads          uint8       `json:"argon2_threads" yaml:"argon2_threads" toml:"argon2_threads"`
+	SessionTimeoutMinutes  int         `json:"session_timeout_minutes" yaml:"session_timeout_minutes" toml:"session_timeout_minutes"`
+	MaxLoginAttempts       int         `json:"max_login_attempts" yaml:"max_login_attempts" toml:"max_login_attempts"`
+	LockoutDurationMinutes int         `json:"lockout_duration_minutes" yaml:"lockout_duration_minutes" toml:"lockout_duration_minutes"`
+	URLConfigs             []URLConfig `json:"url_configs" yaml:"url_configs" toml:"url_configs"`
+	Recipients             []string    `json:"recipients" yaml:"recipients" toml:"recipients"`
+	ErrorRecipient         string      `json:"error_recipient" yaml:"error_recipient" toml:"error_recipient"`
+	BrevoAPIKey            string      `json:"brevo_api_key" yaml:"brevo_api_key" toml:"brevo_api_key"`
+	SenderEmail            string      `json:"sender_email" yaml:"sender_email" toml:"sender_email"`
+	SenderName             string      `json:"sender_name" yaml:"sender_name" toml:"sender_name"`
+	StateFilePath          string      `json:"state_file_path" yaml:"state_file_path" toml:"state_file_path"` // Path to persist state across restarts
+
+	// Localization
+	DefaultLanguage    string            `json:"default_language" yaml:"default_language" toml:"default_language"`          // Email language when no per-recipient preference is set
+	UILanguage         string            `json:"ui_language" yaml:"ui_language" toml:"ui_language"`                         // Web UI language when the visitor has not chosen one
+	RecipientLanguages map[string]string `json:"recipient_languages" yaml:"recipient_languages" toml:"recipient_languages"` // Per-recipient language preference (email -> locale)
+
+	// Self-service subscriptions
+	SubscriptionsEnabled      bool   `json:"subscriptions_enabled" yaml:"subscriptions_enabled" toml:"subscriptions_enabled"`                      // Public subscribe form with double opt-in
+	PublicBaseURL             string `json:"public_base_url" yaml:"public_base_url" toml:"public_base_url"`                                        // Externally reachable base URL used in email links
+	SubscriptionSecret        string `json:"subscription_secret" yaml:"subscription_secret" toml:"subscription_secret"`                            // HMAC key for signed unsubscribe links
+	MaxSubscribers            int    `json:"max_subscribers" yaml:"max_subscribers" toml:"max_subscribers"`                                        // Upper bound on confirmed subscribers (one alert to all of them must fit email_rate_limit_per_hour)
+	SubscriptionEmailsPerHour int    `json:"subscription_emails_per_hour" yaml:"subscription_emails_per_hour" toml:"subscription_emails_per_hour"` // Confirmation emails per hour (separate from the alert budget in email_rate_limit_per_hour)
+
+	// Prometheus metrics
+	MetricsEnabled     bool   `json:"metrics_enabled" yaml:"metrics_enabled" toml:"metrics_enabled"`                // Expose /metrics
+	MetricsListen      string `json:"metrics_listen" yaml:"metrics_listen" toml:"metrics_listen"`                   // Dedicated listen address (empty = serve on http_listen)
+	MetricsBearerToken string `json:"metrics_bearer_token" yaml:"metrics_bearer_token" toml:"metrics_bearer_token"` // Required "Authorization: Bearer" token (empty = no auth)
+
+	// Secrets read from files (e.g. systemd credentials); when set they take precedence over the inline value
+	PasswordHashFile       string `json:"password_hash_file" yaml:"password_hash_file" toml:"password_hash_file"`
+	BrevoAPIKeyFile        string `json:"brevo_api_key_file" yaml:"brevo_api_key_file" toml:"brevo_api_key_file"`
+	SubscriptionSecretFile string `json:"subscription_secret_file" yaml:"subscription_secret_file" toml:"subscription_secret_file"`
+	MetricsBearerTokenFile string `json:"metrics_bearer_token_file" yaml:"metrics_bearer_token_file" toml:"metrics_bearer_token_file"`
+
+	// Configuration reload
+	ConfigWatchIntervalSeconds int `json:"config_watch_interval_seconds" yaml:"config_watch_interval_seconds" toml:"config_watch_interval_seconds"` // Poll the config file for changes (0 = only reload on SIGHUP)
+
+	// URL detail page
+	CheckHistorySize            int  `json:"check_history_size" yaml:"check_history_size" toml:"check_history_size"`                                     // Checks kept per URL for the detail page
+	SnapshotHistorySize         int  `json:"snapshot_history_size" yaml:"snapshot_history_size" toml:"snapshot_history_size"`                            // Distinct page versions kept per URL for the diff view
+	StructureChangeAlerts       bool `json:"structure_change_alerts" yaml:"structure_change_alerts" toml:"structure_change_alerts"`                      // Email error_recipient when tables or section markers disappear
+	StructureAlertCooldownHours int  `json:"structure_alert_cooldown_hours" yaml:"structure_alert_cooldown_hours" toml:"structure_alert_cooldown_hours"` // Minimum time between structure change emails for a URL
+
+	// Failure handling
+	CheckRetries        int `json:"check_retries" yaml:"check_retries" toml:"check_retries"`                         // Extra attempts when a check gets no response or a 5xx (0 = no retries)
+	RetryBackoffSeconds int `json:"retry_backoff_seconds" yaml:"retry_backoff_seconds" toml:"retry_backoff_seconds"` // Delay before the first retry, doubled per retry and jittered
+	FailureThreshold    int `json:"failure_threshold" yaml:"failure_threshold" toml:"failure_threshold"`             // Consecutive failed checks before a URL is considered unreachable
+	FlapWindowMinutes   int `json:"flap_window_minutes" yaml:"flap_window_minutes" toml:"flap_window_minutes"`       // Window in which reachability changes are counted
+	FlapThreshold       int `json:"flap_threshold" yaml:"flap_threshold" toml:"flap_threshold"`                      // Changes within the window that make a URL flapping (no error/recovery emails)
+
+	// Upstream throttling (HTTP 429, or 503 with Retry-After)
+	RetryAfterDefaultSeconds int `json:"retry_after_default_seconds" yaml:"retry_after_default_seconds" toml:"retry_after_default_seconds"` // Pause after a 429 without a usable Retry-After
+	RetryAfterMaxSeconds     int `json:"retry_after_max_seconds" yaml:"retry_after_max_seconds" toml:"retry_after_max_seconds"`             // Longest pause honoured, whatever Retry-After asks for
+
+	// Outbound proxy (per-URL "proxy" overrides it)
+	ProxyURL            string `json:"proxy_url" yaml:"proxy_url" toml:"proxy_url"`                                     // http(s):// (CONNECT) or socks5:// proxy with optional user:pass@ (empty = HTTP_PROXY/HTTPS_PROXY environment)
+	ProxyFailoverDirect bool   `json:"proxy_failover_direct" yaml:"proxy_failover_direct" toml:"proxy_failover_direct"` // Connect directly when the proxy cannot be reached
+
+	// Response validation
+	MaxBodyKB           int      `json:"max_body_kb" yaml:"max_body_kb" toml:"max_body_kb"`                               // Larger responses fail the check
+	AllowedContentTypes []string `json:"allowed_content_types" yaml:"allowed_content_types" toml:"allowed_content_types"` // Accepted Content-Type media types (a missing header is accepted)
+	SoftErrorMarkers    []string `json:"soft_error_markers" yaml:"soft_error_markers" toml:"soft_error_markers"`          // Phrases in a page's title or main heading that mark a maintenance page
+
+	// User-Agent list (user_agent_rotation_enabled)
+	UserAgentSource       string `json:"user_agent_source" yaml:"user_agent_source" toml:"user_agent_source"`                      // URL or local file with the User-Agent list (empty = microlinkhq/top-user-agents)
+	UserAgentCacheFile    string `json:"user_agent_cache_file" yaml:"user_agent_cache_file" toml:"user_agent_cache_file"`          // Last fetched list, used at startup (default: user_agents.json next to the state file)
+	UserAgentRefreshHours int    `json:"user_agent_refresh_hours" yaml:"user_agent_refresh_hours" toml:"user_agent_refresh_hours"` // How often the list is fetched again
+	UserAgentStaleDays    int    `json:"user_agent_stale_days" yaml:"user_agent_stale_days" toml:"user_agent_stale_days"`          // Email error_recipient about failed fetches only once the list in use is this old
+
+	// Adaptive polling (URLs without a schedule)
+	AdaptivePolling             bool `json:"adaptive_polling" yaml:"adaptive_polling" toml:"adaptive_polling"`                                           // Check faster during incidents and back off while pages stay the same
+	AdaptiveFastIntervalSeconds int  `json:"adaptive_fast_interval_seconds" yaml:"adaptive_fast_interval_seconds" toml:"adaptive_fast_interval_seconds"` // Interval while the search terms are found or an incident time is near
+	AdaptiveMaxIntervalSeconds  int  `json:"adaptive_max_interval_seconds" yaml:"adaptive_max_interval_seconds" toml:"adaptive_max_interval_seconds"`    // Longest interval the backoff reaches on unchanged pages
+	AdaptiveNearMinutes         int  `json:"adaptive_near_minutes" yaml:"adaptive_near_minutes" toml:"adaptive_near_minutes"`                            // How close to a time in the incident (e.g. the "До 14:00" repair ETA) counts as near
 }
 
-// loadConfig loads configuration from a JSON file
+// defaultConfigPath is the config file read when -config is not given (relative to the working directory)
+const defaultConfigPath = "config.json"
+
+// loadConfig loads configuration from a JSON, YAML or TOML file, applying NESTANAK_* environment overrides and *_file secrets
 func loadConfig(filename string) (Config, error) {
+	config, _, err := loadConfigWithSources(filename)
+	return config, err
+}
+
+// loadConfigWithSources loads configuration like loadConfig and also reports where each setting
+// came from (JSON name -> source); settings missing from the map use their default
+func loadConfigWithSources(filename string) (Config, map[string]string, error) {
 	var config Config
+	sources := make(map[string]string)
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return config, sources, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	present, err := decodeConfig(filename, data, &config)
+	if err != nil {
+		return config, sources, fmt.Errorf("failed to parse config file: %v", err)
+	}
+	for _, setting := range present {
+		sources[setting] = sourceFile
+	}
+
+	// Environment beats the file; a *_file secret beats both
+	if err := applyEnvOverrides(&config, sources); err != nil {
+		return config, sources, err
+	}
+	if err := resolveSecretFiles(&config, sources); err != nil {
+		return config, sources, err
+	}
+
+	applyConfigDefaults(&config)
+
+	return config, sources, nil
+}
+
+// saveURLConfigs replaces url_configs in the config file, leaving every other setting
+// (including key order and formatting) untouched, and writes the file atomically
+// Only JSON files are supported; rewriting YAML/TOML would lose their comments
+func saveURLConfigs(filename string, urlConfigs []URLConfig) error {
+	if configFormat(filename) != configFormatJSON {
+		return fmt.Errorf("URLs can only be edited here when the config file is JSON; edit %s directly", filepath.Base(filename))
+	}
 
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return config, fmt.Errorf("failed to read config file: %v", err)
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fmt.Errorf("failed to stat config file: %v", err)
+	}
+
+	// Locate the url_configs value among the top-level keys
+	start, end := -1, -1
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("failed to parse config file: top level is not an object")
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse config file: %v", err)
+		}
+		keyEnd := int(decoder.InputOffset())
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("failed to parse config file: %v", err)
+		}
+		if token == "url_configs" {
+			end = int(decoder.InputOffset())
+			start = keyEnd + bytes.Index(data[keyEnd:end], value[:1])
+			break
+		}
+	}
+	if start < 0 {
+		return fmt.Errorf("url_configs not found in %s", filename)
+	}
+
+	// Same layout as the sample config; keep "&" in URLs readable
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("  ", "  ")
+	if err := encoder.Encode(urlConfigs); err != nil {
+		return fmt.Errorf("failed to encode url_configs: %v", err)
+	}
+
+	updated := make([]byte, 0, len(data)+encoded.Len())
+	updated = append(updated, data[:start]...)
+	updated = append(updated, bytes.TrimRight(encoded.Bytes(), "\n")...)
+	updated = append(updated, data[end:]...)
+
+	// Write to a temporary file in the same directory, then rename over the original
+	tempFile, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary config file: %v", err)
+	}
+	defer os.Remove(tempFile.Name()) // No-op after a successful rename
+
+	if _, err := tempFile.Write(updated); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to sync config file: %v", err)
 	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	if err := os.Chmod(tempFile.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set config file permissions: %v", err)
+	}
+	if err := os.Rename(tempFile.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace config file: %v", err)
+	}
+	return nil
+}
 
-	if err := json.Unmarshal(data, &config); err != nil {
-		return config, fmt.Errorf("failed to parse config file: %v", err)
+// maxConfiguredRecipients returns the size of the largest recipient list an alert can go to
+// (the global recipients or a URL's own list)
+func maxConfiguredRecipients(config Config) int {
+	largest := len(config.Recipients)
+	for _, urlConfig := range config.URLConfigs {
+		largest = max(largest, len(urlConfig.Recipients))
 	}
+	return largest
+}
 
-	return config, nil
+// applyConfigDefaults fills in optional settings that are missing from the config file
+func applyConfigDefaults(config *Config) {
+	if config.DefaultLanguage == "" {
+		config.DefaultLanguage = LocaleSrLatn
+	}
+	if config.UILanguage == "" {
+		config.UILanguage = LocaleEn
+	}
+	if config.MaxSubscribers == 0 {
+		// Whatever the hourly email budget leaves after the configured recipients
+		config.MaxSubscribers = max(1, config.EmailRateLimitPerHour-maxConfiguredRecipients(*config))
+	}
+	if config.SubscriptionEmailsPerHour == 0 {
+		config.SubscriptionEmailsPerHour = 20
+	}
+	if config.CheckHistorySize == 0 {
+		config.CheckHistorySize = 20
+	}
+	if config.SnapshotHistorySize == 0 {
+		config.SnapshotHistorySize = 5
+	}
+	if config.StructureAlertCooldownHours == 0 {
+		config.StructureAlertCooldownHours = 24
+	}
+	if config.RetryBackoffSeconds == 0 {
+		config.RetryBackoffSeconds = 2
+	}
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = 2
+	}
+	if config.FlapWindowMinutes == 0 {
+		config.FlapWindowMinutes = 60
+	}
+	if config.FlapThreshold == 0 {
+		config.FlapThreshold = 4
+	}
+	if config.RetryAfterDefaultSeconds == 0 {
+		config.RetryAfterDefaultSeconds = 300
+	}
+	if config.RetryAfterMaxSeconds == 0 {
+		config.RetryAfterMaxSeconds = 3600
+	}
+	if config.MaxBodyKB == 0 {
+		config.MaxBodyKB = 4096
+	}
+	if config.AllowedContentTypes == nil {
+		config.AllowedContentTypes = defaultAllowedContentTypes
+	}
+	if config.SoftErrorMarkers == nil {
+		config.SoftErrorMarkers = defaultSoftErrorMarkers
+	}
+	if config.UserAgentCacheFile == "" {
+		config.UserAgentCacheFile = filepath.Join(filepath.Dir(config.StateFilePath), "user_agents.json")
+	}
+	if config.UserAgentRefreshHours == 0 {
+		config.UserAgentRefreshHours = 24
+	}
+	if config.UserAgentStaleDays == 0 {
+		config.UserAgentStaleDays = 7
+	}
+	if config.AdaptiveFastIntervalSeconds == 0 {
+		config.AdaptiveFastIntervalSeconds = 60
+	}
+	if config.AdaptiveMaxIntervalSeconds == 0 {
+		config.AdaptiveMaxIntervalSeconds = 3600
+	}
+	if config.AdaptiveNearMinutes == 0 {
+		config.AdaptiveNearMinutes = 30
+	}
+	config.PublicBaseURL = strings.TrimRight(config.PublicBaseURL, "/")
 }
 
 // ValidateConfig validates the configuration
@@ -110,6 +454,76 @@ func ValidateConfig(config Config) error {
 	if config.UserAgentPoolSize < 1 || config.UserAgentPoolSize > 100 {
 		errors = append(errors, "user_agent_pool_size must be between 1 and 100")
 	}
+	if config.CheckHistorySize < 1 || config.CheckHistorySize > 500 {
+		errors = append(errors, "check_history_size must be between 1 and 500")
+	}
+	if config.ConfigWatchIntervalSeconds < 0 || config.ConfigWatchIntervalSeconds > 3600 {
+		errors = append(errors, "config_watch_interval_seconds must be between 0 (disabled) and 3600")
+	}
+	if config.SnapshotHistorySize < 2 || config.SnapshotHistorySize > 20 {
+		errors = append(errors, "snapshot_history_size must be between 2 and 20")
+	}
+	if config.CheckRetries < 0 || config.CheckRetries > 5 {
+		errors = append(errors, "check_retries must be between 0 and 5")
+	}
+	if config.RetryBackoffSeconds < 1 || config.RetryBackoffSeconds > 30 {
+		errors = append(errors, "retry_backoff_seconds must be between 1 and 30")
+	}
+	if config.FailureThreshold < 1 || config.FailureThreshold > 10 {
+		errors = append(errors, "failure_threshold must be between 1 and 10")
+	}
+	if config.FlapWindowMinutes < 5 || config.FlapWindowMinutes > 1440 {
+		errors = append(errors, "flap_window_minutes must be between 5 and 1440")
+	}
+	if config.FlapThreshold < 2 || config.FlapThreshold > 20 {
+		errors = append(errors, "flap_threshold must be between 2 and 20")
+	}
+	if config.RetryAfterDefaultSeconds < 5 || config.RetryAfterDefaultSeconds > 86400 {
+		errors = append(errors, "retry_after_default_seconds must be between 5 and 86400")
+	}
+	if config.RetryAfterMaxSeconds < 60 || config.RetryAfterMaxSeconds > 86400 {
+		errors = append(errors, "retry_after_max_seconds must be between 60 and 86400")
+	}
+	if config.RetryAfterDefaultSeconds > config.RetryAfterMaxSeconds {
+		errors = append(errors, "retry_after_default_seconds cannot be more than retry_after_max_seconds")
+	}
+	if config.ProxyURL != "" {
+		if err := validateProxyURL(config.ProxyURL); err != nil {
+			errors = append(errors, fmt.Sprintf("proxy_url is invalid: %v", err))
+		}
+	}
+	if config.MaxBodyKB < 64 || config.MaxBodyKB > 65536 {
+		errors = append(errors, "max_body_kb must be between 64 and 65536")
+	}
+	if len(config.AllowedContentTypes) == 0 {
+		errors = append(errors, "allowed_content_types must list at least one media type")
+	}
+	for _, contentType := range config.AllowedContentTypes {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != contentType {
+			errors = append(errors, fmt.Sprintf("allowed_content_types: %q is not a lowercase media type such as text/html", contentType))
+		}
+	}
+	if strings.Contains(config.UserAgentSource, "://") && !isRemoteSource(config.UserAgentSource) {
+		errors = append(errors, "user_agent_source must be an http(s) URL or a local file path")
+	}
+	if config.UserAgentRefreshHours < 1 || config.UserAgentRefreshHours > 720 {
+		errors = append(errors, "user_agent_refresh_hours must be between 1 and 720")
+	}
+	if config.UserAgentStaleDays < 1 || config.UserAgentStaleDays > 90 {
+		errors = append(errors, "user_agent_stale_days must be between 1 and 90")
+	}
+	if config.AdaptiveFastIntervalSeconds < 5 || config.AdaptiveFastIntervalSeconds > 3600 {
+		errors = append(errors, "adaptive_fast_interval_seconds must be between 5 and 3600")
+	}
+	if config.AdaptiveMaxIntervalSeconds < 60 || config.AdaptiveMaxIntervalSeconds > 86400 {
+		errors = append(errors, "adaptive_max_interval_seconds must be between 60 and 86400")
+	}
+	if config.AdaptiveMaxIntervalSeconds < config.AdaptiveFastIntervalSeconds {
+		errors = append(errors, "adaptive_max_interval_seconds cannot be less than adaptive_fast_interval_seconds")
+	}
+	if config.AdaptiveNearMinutes < 1 || config.AdaptiveNearMinutes > 720 {
+		errors = append(errors, "adaptive_near_minutes must be between 1 and 720")
+	}
 
 	// Validate email config
 	if config.BrevoAPIKey == "" || config.BrevoAPIKey == "YOUR_BREVO_API_KEY_HERE" {
@@ -132,6 +546,63 @@ func ValidateConfig(config Config) error {
 	if config.ErrorRecipient != "" && !strings.Contains(config.ErrorRecipient, "@") {
 		errors = append(errors, "error_recipient must be a valid email address")
 	}
+	if config.StructureAlertCooldownHours < 1 || config.StructureAlertCooldownHours > 24 {
+		errors = append(errors, "structure_alert_cooldown_hours must be between 1 and 24")
+	}
+	if config.StructureChangeAlerts && config.ErrorRecipient == "" {
+		errors = append(errors, "structure_change_alerts requires error_recipient")
+	}
+
+	// Validate language settings
+	if normalizeLocale(config.DefaultLanguage) != config.DefaultLanguage {
+		errors = append(errors, fmt.Sprintf("default_language must be one of: %s", strings.Join(supportedLocales, ", ")))
+	}
+	if normalizeLocale(config.UILanguage) != config.UILanguage {
+		errors = append(errors, fmt.Sprintf("ui_language must be one of: %s", strings.Join(supportedLocales, ", ")))
+	}
+	for email, lang := range config.RecipientLanguages {
+		if !strings.Contains(email, "@") {
+			errors = append(errors, fmt.Sprintf("recipient_languages key %q must be a valid email address", email))
+		}
+		if normalizeLocale(lang) == "" {
+			errors = append(errors, fmt.Sprintf("recipient_languages[%q] must be one of: %s", email, strings.Join(supportedLocales, ", ")))
+		}
+	}
+
+	// Validate subscription settings
+	if config.SubscriptionsEnabled {
+		if !config.HTTPEnabled {
+			errors = append(errors, "subscriptions_enabled requires http_enabled")
+		}
+		if !strings.HasPrefix(config.PublicBaseURL, "http://") && !strings.HasPrefix(config.PublicBaseURL, "https://") {
+			errors = append(errors, "public_base_url must start with http:// or https:// when subscriptions_enabled is true")
+		}
+		if len(config.SubscriptionSecret) < 32 {
+			errors = append(errors, "subscription_secret must be at least 32 characters when subscriptions_enabled is true")
+		}
+		if config.MaxSubscribers < 1 {
+			errors = append(errors, "max_subscribers must be at least 1")
+		}
+		if recipients := maxConfiguredRecipients(config); config.MaxSubscribers+recipients > config.EmailRateLimitPerHour {
+			errors = append(errors, fmt.Sprintf("max_subscribers plus %d recipients exceeds email_rate_limit_per_hour (%d), so one alert could not reach every subscriber", recipients, config.EmailRateLimitPerHour))
+		}
+		if config.SubscriptionEmailsPerHour < 1 || config.SubscriptionEmailsPerHour > 300 {
+			errors = append(errors, "subscription_emails_per_hour must be between 1 and 300")
+		}
+	}
+
+	// Validate metrics settings
+	if config.MetricsEnabled {
+		if config.MetricsListen == "" && !config.HTTPEnabled {
+			errors = append(errors, "metrics_enabled requires http_enabled or a dedicated metrics_listen address")
+		}
+		if config.MetricsListen != "" && config.MetricsListen == config.HTTPListen {
+			errors = append(errors, "metrics_listen must differ from http_listen (leave it empty to share the web server)")
+		}
+		if config.MetricsBearerToken != "" && len(config.MetricsBearerToken) < 16 {
+			errors = append(errors, "metrics_bearer_token must be at least 16 characters")
+		}
+	}
 
 	// Validate authentication settings
 	if config.AuthEnabled {
@@ -195,6 +666,54 @@ func ValidateConfig(config Config) error {
 				errors = append(errors, fmt.Sprintf("url_configs[%d].search_terms[%d] cannot be empty", i, j))
 			}
 		}
+		if urlConfig.Extractor != "" && !slices.Contains(extractors, urlConfig.Extractor) {
+			errors = append(errors, fmt.Sprintf("url_configs[%d].extractor must be one of: %s", i, strings.Join(extractors, ", ")))
+		}
+
+		// Validate per-URL overrides with the same limits as the global settings
+		if urlConfig.Schedule != "" {
+			if _, err := parseSchedule(urlConfig.Schedule); err != nil {
+				errors = append(errors, fmt.Sprintf("url_configs[%d].schedule: %v", i, err))
+			}
+			if urlConfig.CheckIntervalSeconds != 0 {
+				errors = append(errors, fmt.Sprintf("url_configs[%d] cannot set both schedule and check_interval_seconds", i))
+			}
+		}
+		if urlConfig.CheckIntervalSeconds != 0 && urlConfig.CheckIntervalSeconds < 5 {
+			errors = append(errors, fmt.Sprintf("url_configs[%d].check_interval_seconds should be at least 5 seconds for reliability", i))
+		}
+		if urlConfig.ConnectTimeout != 0 && (urlConfig.ConnectTimeout < 1 || urlConfig.ConnectTimeout > 60) {
+			errors = append(errors, fmt.Sprintf("url_configs[%d].connect_timeout must be between 1 and 60 seconds", i))
+		}
+		if urlConfig.AlertCooldownMinutes < 0 {
+			errors = append(errors, fmt.Sprintf("url_configs[%d].alert_cooldown_minutes cannot be negative", i))
+		}
+		if urlConfig.MaxEmailsPerDay != 0 && (urlConfig.MaxEmailsPerDay < 1 || urlConfig.MaxEmailsPerDay > 10) {
+			errors = append(errors, fmt.Sprintf("url_configs[%d].max_emails_per_day must be between 1 and 10", i))
+		}
+		for j, recipient := range urlConfig.Recipients {
+			if !strings.Contains(recipient, "@") {
+				errors = append(errors, fmt.Sprintf("url_configs[%d].recipients[%d] must be a valid email address", i, j))
+			}
+		}
+		if err := validateHeaders(urlConfig.Headers); err != nil {
+			errors = append(errors, fmt.Sprintf("url_configs[%d].headers: %v", i, err))
+		}
+		if urlConfig.hasTLSOptions() {
+			if !strings.HasPrefix(urlConfig.URL, "https://") {
+				errors = append(errors, fmt.Sprintf("url_configs[%d] has TLS options but is not an https:// URL", i))
+			}
+			if urlConfig.CAFile != "" && urlConfig.PinnedCertSHA256 != "" {
+				errors = append(errors, fmt.Sprintf("url_configs[%d]: ca_file and pinned_cert_sha256 cannot be combined", i))
+			} else if _, err := urlTLSConfig(urlConfig); err != nil {
+				errors = append(errors, fmt.Sprintf("url_configs[%d]: %v", i, err))
+			}
+		}
+		if urlConfig.Proxy != "" && urlConfig.Proxy != proxyDirect {
+			if err := validateProxyURL(urlConfig.Proxy); err != nil {
+				errors = append(errors, fmt.Sprintf("url_configs[%d].proxy must be a proxy URL or \"direct\": %v", i, err))
+			}
+		}
 	}
 
 	if len(errors) > 0 {
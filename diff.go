@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// structureMarkers are the page headings the extractors look for that are on the page at all times
+// (text that only appears while an outage is listed, like the water outage section, does not belong here)
+var structureMarkers = []string{
+	"Планирана искључења за датум:", // extractDate (power)
+}
+
+// structureAlertType is the alert type structure change emails are recorded under
+const structureAlertType = "structure"
+
+// maxDiffCells caps the LCS table (lines × lines) before falling back to a delete-all/add-all diff
+const maxDiffCells = 4_000_000
+
+// diffContextLines is how many unchanged lines are shown around each change
+const diffContextLines = 3
+
+// analyzeStructure counts tables and looks for the section markers in a page
+func analyzeStructure(htmlContent string) PageStructure {
+	structure := PageStructure{Markers: make([]string, 0)}
+
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return structure
+	}
+
+	var text strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "table" {
+			structure.Tables++
+		}
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+			text.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	pageText := text.String()
+	for _, marker := range structureMarkers {
+		if strings.Contains(pageText, marker) {
+			structure.Markers = append(structure.Markers, marker)
+		}
+	}
+	return structure
+}
+
+// structureChanges describes landmarks present in previous that are missing from current
+func structureChanges(locale string, previous, current PageStructure) []string {
+	changes := make([]string, 0)
+
+	if previous.Tables > 0 && current.Tables == 0 {
+		changes = append(changes, translate(locale, "email.structure.tables", previous.Tables, current.Tables))
+	}
+
+	present := make(map[string]bool)
+	for _, marker := range current.Markers {
+		present[marker] = true
+	}
+	for _, marker := range previous.Markers {
+		if !present[marker] {
+			changes = append(changes, translate(locale, "email.structure.marker", marker))
+		}
+	}
+	return changes
+}
+
+// handleStructureChange logs and (rate limited) emails landmarks that disappeared from a page
+func (m *Monitor) handleStructureChange(result URLCheckResult, previous, current PageStructure) {
+	changes := structureChanges(LocaleEn, previous, current)
+	if len(changes) == 0 {
+		return
+	}
+
+	log.Printf("🧩 Page structure changed for %s: %s", result.URL, strings.Join(changes, "; "))
+	m.addLog(fmt.Sprintf("Page structure changed for %s: %s", result.URL, strings.Join(changes, "; ")))
+
+	if !m.getConfig().StructureChangeAlerts || !m.canSendStructureAlert(result.URL) {
+		return
+	}
+
+	displayName := result.Name
+	if displayName == "" {
+		displayName = result.URL
+	}
+
+	locale := m.recipientLocale(m.getConfig().ErrorRecipient)
+	subject := translate(locale, "email.structure.subject", displayName)
+	details := "- " + strings.Join(structureChanges(locale, previous, current), "\n- ")
+	body := translate(locale, "email.structure.body", displayName, result.URL, details, m.formatLocalTime(result.CheckedAt))
+
+	if err := m.deliverEmail("structure", m.getConfig().ErrorRecipient, subject, body, nil); err != nil {
+		log.Printf("Failed to send structure change email to %s: %v", m.getConfig().ErrorRecipient, err)
+		return
+	}
+	log.Printf("📧 Structure change notification sent to %s for %s", m.getConfig().ErrorRecipient, displayName)
+	m.recordEmailNotification(result.URL, result.Name, []string{m.getConfig().ErrorRecipient}, 0, "structure", subject)
+	m.recordStructureAlert(result.URL)
+	go m.saveState()
+}
+
+// canSendStructureAlert checks the structure alert cooldown of a URL
+// It is separate from the error email limit, so structure alerts never hold back unreachable/recovery emails
+func (m *Monitor) canSendStructureAlert(url string) bool {
+	m.mu.RLock()
+	lastAlert := m.lastAlertTime[AlertKey{URL: url, AlertType: structureAlertType}]
+	m.mu.RUnlock()
+
+	// The persisted time survives restarts
+	if m.state != nil {
+		if persisted, exists := m.state.GetLastAlertTime(url, structureAlertType); exists && persisted.After(lastAlert) {
+			lastAlert = persisted
+		}
+	}
+
+	cooldown := time.Duration(m.getConfig().StructureAlertCooldownHours) * time.Hour
+	if time.Since(lastAlert) < cooldown {
+		log.Printf("⏱️  Structure alert cooldown active for %s", url)
+		return false
+	}
+	return true
+}
+
+// recordStructureAlert records that a structure change email was sent for a URL
+func (m *Monitor) recordStructureAlert(url string) {
+	m.mu.Lock()
+	m.lastAlertTime[AlertKey{URL: url, AlertType: structureAlertType}] = time.Now()
+	m.mu.Unlock()
+
+	if m.state != nil {
+		m.state.RecordAlertTime(url, structureAlertType)
+	}
+}
+
+// diffLines returns a line diff turning oldText into newText
+func diffLines(oldText, newText string) []DiffLine {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	// Strip the common prefix and suffix so the LCS only covers the changed middle
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, DiffLine{Op: "same", Text: line})
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, DiffLine{Op: "same", Text: line})
+	}
+	return lines
+}
+
+// diffMiddle diffs two line slices using a longest common subsequence table
+func diffMiddle(a, b []string) []DiffLine {
+	lines := make([]DiffLine, 0, len(a)+len(b))
+
+	// Too large to diff line by line: show the whole block as replaced
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, DiffLine{Op: "del", Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, DiffLine{Op: "add", Text: line})
+		}
+		return lines
+	}
+
+	// lcs[i*(len(b)+1)+j] is the LCS length of a[i:] and b[j:]
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: "same", Text: a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			lines = append(lines, DiffLine{Op: "del", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: "add", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: "del", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: "add", Text: b[j]})
+	}
+	return lines
+}
+
+// collapseDiff keeps changed lines plus diffContextLines of context around them,
+// replacing each run of hidden unchanged lines with a single "skip" line
+func collapseDiff(lines []DiffLine) []DiffLine {
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if line.Op == "same" {
+			continue
+		}
+		for k := max(0, i-diffContextLines); k <= min(len(lines)-1, i+diffContextLines); k++ {
+			keep[k] = true
+		}
+	}
+
+	collapsed := make([]DiffLine, 0)
+	hidden := 0
+	for i, line := range lines {
+		if keep[i] {
+			if hidden > 0 {
+				collapsed = append(collapsed, DiffLine{Op: "skip", Text: fmt.Sprintf("%d", hidden)})
+				hidden = 0
+			}
+			collapsed = append(collapsed, line)
+			continue
+		}
+		hidden++
+	}
+	if hidden > 0 && len(collapsed) > 0 {
+		collapsed = append(collapsed, DiffLine{Op: "skip", Text: fmt.Sprintf("%d", hidden)})
+	}
+	return collapsed
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// diffOps renders diff lines as "  same", "- del", "+ add" and "… skip" for compact expectations
+func diffOps(lines []DiffLine) []string {
+	prefixes := map[string]string{"same": "  ", "del": "- ", "add": "+ ", "skip": "… "}
+	ops := make([]string, len(lines))
+	for i, line := range lines {
+		ops[i] = prefixes[line.Op] + line.Text
+	}
+	return ops
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{"identical", "a\nb", "a\nb", []string{"  a", "  b"}},
+		{"both empty", "", "", []string{"  "}},
+		{"from empty", "", "a", []string{"- ", "+ a"}},
+		{"insert in middle", "a\nb\nc", "a\nx\nb\nc", []string{"  a", "+ x", "  b", "  c"}},
+		{"append", "a\nb", "a\nb\nc", []string{"  a", "  b", "+ c"}},
+		{"delete first", "a\nb\nc", "b\nc", []string{"- a", "  b", "  c"}},
+		{"replace puts deletions first", "a\nb\nc", "a\nx\nc", []string{"  a", "- b", "+ x", "  c"}},
+		{"trailing newline kept", "a\n", "a\nb\n", []string{"  a", "+ b", "  "}},
+		{"all changed", "a\nb", "c\nd", []string{"- a", "- b", "+ c", "+ d"}},
+		{"moved line", "x\na\nb", "a\nb\nx", []string{"- x", "  a", "  b", "+ x"}},
+		{"repeated lines", "a\nb\na\nb", "b\na\nb", []string{"- a", "  b", "  a", "  b"}},
+		{"change between equal ends", "h\n1\n2\n3\nf", "h\n1\nX\n3\nf", []string{"  h", "  1", "- 2", "+ X", "  3", "  f"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffOps(diffLines(tt.old, tt.new))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines(%q, %q) = %q, want %q", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesTooLarge(t *testing.T) {
+	// Every line differs, so the changed middle exceeds maxDiffCells and is shown as replaced
+	oldLines := make([]string, 2001)
+	newLines := make([]string, 2001)
+	for i := range oldLines {
+		oldLines[i] = fmt.Sprintf("old %d", i)
+		newLines[i] = fmt.Sprintf("new %d", i)
+	}
+	newLines[1000] = oldLines[1000]
+
+	lines := diffLines("head\n"+strings.Join(oldLines, "\n"), "head\n"+strings.Join(newLines, "\n"))
+	if len(lines) != 1+2*len(oldLines) {
+		t.Fatalf("got %d lines, want %d", len(lines), 1+2*len(oldLines))
+	}
+	if lines[0] != (DiffLine{Op: "same", Text: "head"}) {
+		t.Errorf("common prefix = %+v, want same head", lines[0])
+	}
+	for i, line := range lines[1:] {
+		want := "del"
+		if i >= len(oldLines) {
+			want = "add"
+		}
+		if line.Op != want {
+			t.Fatalf("line %d op = %q, want %q", i+1, line.Op, want)
+		}
+	}
+}
+
+func TestCollapseDiff(t *testing.T) {
+	same := func(n int) []DiffLine {
+		lines := make([]DiffLine, n)
+		for i := range lines {
+			lines[i] = DiffLine{Op: "same", Text: fmt.Sprintf("%d", i)}
+		}
+		return lines
+	}
+	concat := func(parts ...[]DiffLine) []DiffLine {
+		var lines []DiffLine
+		for _, part := range parts {
+			lines = append(lines, part...)
+		}
+		return lines
+	}
+	change := []DiffLine{{Op: "del", Text: "old"}, {Op: "add", Text: "new"}}
+
+	tests := []struct {
+		name  string
+		lines []DiffLine
+		want  []string
+	}{
+		{"no changes", same(10), []string{}},
+		{"short context kept", concat(same(2), change, same(2)), []string{"  0", "  1", "- old", "+ new", "  0", "  1"}},
+		{"long runs skipped", concat(same(10), change, same(10)), []string{"… 7", "  7", "  8", "  9", "- old", "+ new", "  0", "  1", "  2", "… 7"}},
+		{"close changes share context", concat(change, same(6), change), []string{"- old", "+ new", "  0", "  1", "  2", "  3", "  4", "  5", "- old", "+ new"}},
+		{"distant changes split", concat(change, same(7), change), []string{"- old", "+ new", "  0", "  1", "  2", "… 1", "  4", "  5", "  6", "- old", "+ new"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffOps(collapseDiff(tt.lines))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collapseDiff = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
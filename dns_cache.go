@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// dnsStaleGrace is how long an expired entry is kept as a fallback for failed lookups
+const dnsStaleGrace = 24 * time.Hour
+
 // NewDNSCache creates a new DNS cache with specified TTL
 func NewDNSCache(ttl time.Duration) *DNSCache {
 	if ttl == 0 {
@@ -37,10 +40,12 @@ func (dc *DNSCache) Resolve(hostname string) (string, bool, error) {
 		entry.mu.RLock()
 		cachedIP := entry.ResolvedIP
 		entry.mu.RUnlock()
+		dc.hits.Add(1)
 		return cachedIP, false, nil
 	}
 
 	// Need to resolve DNS
+	dc.misses.Add(1)
 	ips, err := net.LookupIP(hostname)
 	if err != nil {
 		// If we have an expired cache entry, use it as fallback
@@ -89,6 +94,7 @@ func (dc *DNSCache) Resolve(hostname string) (string, bool, error) {
 		
 		if oldIP != resolvedIP {
 			ipChanged = true
+			dc.ipChanges.Add(1)
 			log.Printf("🔄 DNS IP changed for %s: %s → %s", hostname, oldIP, resolvedIP)
 		}
 	}
@@ -145,7 +151,8 @@ func (dc *DNSCache) InvalidateCache(hostname string) {
 	}
 }
 
-// CleanupExpired removes expired cache entries (called periodically)
+// CleanupExpired removes entries that expired more than dnsStaleGrace ago (called periodically)
+// Recently expired entries are kept so Resolve can fall back to them when DNS fails
 func (dc *DNSCache) CleanupExpired() {
 	dc.mu.Lock()
 	defer dc.mu.Unlock()
@@ -155,7 +162,7 @@ func (dc *DNSCache) CleanupExpired() {
 	
 	for addr, entry := range dc.entries {
 		entry.mu.RLock()
-		expired := now.After(entry.ExpiresAt)
+		expired := now.After(entry.ExpiresAt.Add(dnsStaleGrace))
 		entry.mu.RUnlock()
 		
 		if expired {
@@ -165,7 +172,7 @@ func (dc *DNSCache) CleanupExpired() {
 	}
 	
 	if removed > 0 {
-		log.Printf("🧹 Cleaned up %d expired DNS cache entries", removed)
+		log.Printf("🧹 Cleaned up %d stale DNS cache entries", removed)
 	}
 }
 
@@ -197,3 +204,14 @@ func (dc *DNSCache) GetCacheInfo() map[string]interface{} {
 	return info
 }
 
+// GetCounters returns cache hits, misses (actual lookups) and IP changes since startup
+func (dc *DNSCache) GetCounters() (hits, misses, ipChanges uint64) {
+	return dc.hits.Load(), dc.misses.Load(), dc.ipChanges.Load()
+}
+
+// EntryCount returns the number of cached hostnames
+func (dc *DNSCache) EntryCount() int {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+	return len(dc.entries)
+}
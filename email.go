@@ -138,86 +138,148 @@ func formatAddresses(addressStr string) string {
 	return result
 }
 
-// sendEmail sends a notification email with extracted information
-func (m *Monitor) sendEmail(result URLCheckResult) error {
+// buildMatchEmail builds the localized subject and body for a match notification
+// Extracted page text is transliterated to Latin for Latin-script locales
+func buildMatchEmail(locale string, result URLCheckResult) (string, string) {
 	var subject, body string
 	
-	// Determine if this is water or power outage
-	isWater := strings.Contains(result.URL, "bvk.rs")
-	isPlanned := strings.Contains(result.URL, "planirani") || strings.Contains(result.URL, "planirana")
+	// Water or power outage follows the extractor the details came from; BVK pages are told apart by their path
+	isWater := result.Extractor == extractorWater
 	isMalfunction := strings.Contains(result.URL, "kvarovi")
+
+	date := localizeExtracted(locale, result.Date)
+	timeStr := localizeExtracted(locale, result.Time)
+	formattedAddress := localizeExtracted(locale, formatAddresses(result.Address))
 	
 	// Build subject and body based on type
-	if isWater && isPlanned {
-		// Water planned work
-		subject = fmt.Sprintf("💧 Planirana iskljucenja vode - %s", result.Date)
+	if isWater && !isMalfunction {
+		// Water planned work (also used for water pages of other providers)
+		subject = translate(locale, "email.water_planned.subject", date)
 		if result.Date == "" {
-			subject = "💧 Planirana iskljucenja vode u Batajnici"
+			subject = translate(locale, "email.water_planned.subject_nodate")
 		}
-		formattedAddress := formatAddresses(result.Address)
-		body = fmt.Sprintf(`Planirana iskljucenja vode u Batajnici:
-
-%s
-
-Vreme: %s
-
-Lokacije - %s`, result.Date, result.Time, formattedAddress)
+		body = translate(locale, "email.water_planned.body", date, timeStr, formattedAddress)
 	} else if isWater && isMalfunction {
 		// Water malfunctions
-		subject = "💧 KVAR - Nema vode u Batajnici"
-		formattedAddress := formatAddresses(result.Address)
-		body = fmt.Sprintf(`Trenutno nema vode na sledecim lokacijama:
-
-%s
-
-Procenjeno vreme popravke: %s
-
-Za vise informacija: https://www.bvk.rs/kvarovi-na-mrezi/`, formattedAddress, result.Time)
+		subject = translate(locale, "email.water_malfunction.subject")
+		body = translate(locale, "email.water_malfunction.body", formattedAddress, timeStr)
 	} else {
 		// Power outage (original)
-		subject = fmt.Sprintf("⚡ Nece biti struje u Batajnici - %s", result.Date)
+		subject = translate(locale, "email.power.subject", date)
 		if result.Date == "" {
-			subject = "⚡ Planirano iskljucenje struje u Batajnici"
+			subject = translate(locale, "email.power.subject_nodate")
 		}
-		
-		// Format addresses nicely
-		formattedAddress := formatAddresses(result.Address)
-		
-		body = fmt.Sprintf(`Nece biti struje u Batajnici:
+		body = translate(locale, "email.power.body", date, timeStr, formattedAddress)
+	}
 
-%s
+	return subject, body
+}
 
-Vreme: %s h
+// alertSendDelay spaces out the emails of one alert so a long recipient list does not burst the Brevo API
+const alertSendDelay = 1 * time.Second
 
-Na adresama - %s`, result.Date, result.Time, formattedAddress)
-	}
+// sendEmail sends a notification email with extracted information
+// Each recipient gets the message in their preferred language, with a signed
+// unsubscribe link when subscriptions are enabled
+// Delivery runs in the background so a long subscriber list does not hold up the URL's check loop
+func (m *Monitor) sendEmail(result URLCheckResult) error {
+	recipients := m.alertRecipients(result)
+	go m.deliverAlert(result, recipients)
+	return nil
+}
 
-	// Send to all recipients with delay between sends
+// deliverAlert sends a match alert to each recipient, one email at a time
+// Every email takes one slot of email_rate_limit_per_hour; configured recipients come first,
+// so when the budget runs out only the remaining subscribers miss the alert
+func (m *Monitor) deliverAlert(result URLCheckResult, recipients []alertRecipient) {
+	// Subscriber addresses are only counted: notifications are visible on the dashboard and API
 	sentTo := make([]string, 0)
-	for i, recipient := range m.config.Recipients {
-		if err := sendBrevoEmail(m.config, recipient, subject, body); err != nil {
-			log.Printf("Failed to send email to %s: %v", recipient, err)
+	subscribers := 0
+deliver:
+	for i, recipient := range recipients {
+		if !m.reserveAlertEmail() {
+			log.Printf("⚠️  Global email rate limit reached (%d/hour), alert for %s not sent to %d more recipients",
+				m.getConfig().EmailRateLimitPerHour, result.URL, len(recipients)-i)
+			m.addLog(fmt.Sprintf("Email rate limit reached, alert for %s not sent to %d more recipients", result.URL, len(recipients)-i))
+			break
+		}
+
+		subject, body := buildMatchEmail(recipient.Locale, result)
+
+		var headers map[string]interface{}
+		if unsubscribeURL := m.unsubscribeURL(recipient.Email); unsubscribeURL != "" {
+			body += translate(recipient.Locale, "email.unsubscribe_footer", unsubscribeURL)
+			headers = map[string]interface{}{
+				"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+				"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+			}
+		}
+
+		if err := m.deliverEmail("match", recipient.Email, subject, body, headers); err != nil {
+			log.Printf("Failed to send email to %s: %v", recipient.Email, err)
 		} else {
-			log.Printf("📧 Email sent to %s", recipient)
-			sentTo = append(sentTo, recipient)
+			log.Printf("📧 Email sent to %s", recipient.Email)
+			if recipient.Subscriber {
+				subscribers++
+			} else {
+				sentTo = append(sentTo, recipient.Email)
+			}
 		}
 		
 		// Add delay between emails (except after the last one)
-		if i < len(m.config.Recipients)-1 {
-			time.Sleep(1 * time.Second)
+		if i < len(recipients)-1 {
+			select {
+			case <-time.After(alertSendDelay):
+			case <-m.stopChan:
+				log.Printf("🛑 Shutting down, alert for %s not sent to %d more recipients", result.URL, len(recipients)-i-1)
+				break deliver
+			}
 		}
 	}
 
-	// Record notification if any emails were sent
-	if len(sentTo) > 0 {
-		m.recordEmailNotification(result.URL, result.Name, sentTo, "match", subject)
+	// Record notification if any emails were sent (subject shown in the default language)
+	if len(sentTo) > 0 || subscribers > 0 {
+		subject, _ := buildMatchEmail(m.getConfig().DefaultLanguage, result)
+		m.recordEmailNotification(result.URL, result.Name, sentTo, subscribers, "match", subject)
 	}
+}
 
-	return nil
+// deliverEmail sends an email via Brevo and records the outcome in metrics
+// emailType is one of "match", "error", "recovery", "confirmation"
+func (m *Monitor) deliverEmail(emailType, to, subject, body string, headers map[string]interface{}) error {
+	err := sendBrevoEmailWithHeaders(*m.getConfig(), to, subject, body, headers)
+	m.metrics.RecordEmail(emailType, err)
+
+	// Remember the outcome for the readiness probe
+	m.mu.Lock()
+	if err != nil {
+		m.lastEmailFailure = time.Now()
+		m.lastEmailError = err.Error()
+	} else {
+		m.lastEmailSuccess = time.Now()
+	}
+	m.mu.Unlock()
+
+	// Successful deliveries are published by recordEmailNotification once per alert
+	if err != nil {
+		m.events.Publish("notification", sseNotificationEvent{
+			Outcome:   "failed",
+			Type:      emailType,
+			Timestamp: m.formatLocalTime(time.Now()),
+			Error:     err.Error(),
+		})
+	}
+
+	return err
 }
 
 // sendBrevoEmail sends an email using Brevo API
 func sendBrevoEmail(config Config, to, subject, body string) error {
+	return sendBrevoEmailWithHeaders(config, to, subject, body, nil)
+}
+
+// sendBrevoEmailWithHeaders sends an email using Brevo API with extra mail headers
+func sendBrevoEmailWithHeaders(config Config, to, subject, body string, headers map[string]interface{}) error {
 	// Create Brevo client
 	cfg := lib.NewConfiguration()
 	cfg.AddDefaultHeader("api-key", config.BrevoAPIKey)
@@ -240,6 +302,7 @@ func sendBrevoEmail(config Config, to, subject, body string) error {
 		To:          []lib.SendSmtpEmailTo{recipient},
 		Subject:     subject,
 		TextContent: body,
+		Headers:     headers,
 	}
 
 	// Send email
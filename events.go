@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"time"
+)
+
+// sseKeepaliveInterval keeps idle connections open through proxies
+const sseKeepaliveInterval = 25 * time.Second
+
+// sseClientBuffer is how many events a slow client may lag behind before events are dropped
+const sseClientBuffer = 32
+
+// maxSSEClients caps concurrent /events connections
+const maxSSEClients = 50
+
+// NewEventBroker creates a broker that accepts up to maxClients subscribers
+func NewEventBroker(maxClients int) *EventBroker {
+	return &EventBroker{
+		clients:    make(map[chan ServerEvent]struct{}),
+		maxClients: maxClients,
+	}
+}
+
+// Subscribe registers a new client, returning nil if the client limit is reached
+func (eb *EventBroker) Subscribe() chan ServerEvent {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if len(eb.clients) >= eb.maxClients {
+		return nil
+	}
+	ch := make(chan ServerEvent, sseClientBuffer)
+	eb.clients[ch] = struct{}{}
+	return ch
+}
+
+// Unsubscribe removes a client
+func (eb *EventBroker) Unsubscribe(ch chan ServerEvent) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	delete(eb.clients, ch)
+}
+
+// Publish sends an event to every client without blocking; slow clients miss the event
+func (eb *EventBroker) Publish(eventType string, payload interface{}) {
+	if eb == nil {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("⚠️  Failed to encode %s event: %v", eventType, err)
+		return
+	}
+	event := ServerEvent{Type: eventType, Data: data}
+
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	for ch := range eb.clients {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// sseCheckEvent is published after every URL check
+type sseCheckEvent struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	Found       bool   `json:"found"`
+	Unreachable bool   `json:"unreachable"`
+	LastCheck   string `json:"last_check"` // Display time (time_offset_hours applied)
+	NextCheck   string `json:"next_check"`
+	ResponseMs  int64  `json:"response_ms"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Throttled   string `json:"throttled_until,omitempty"` // Display time checks are paused until (Retry-After)
+}
+
+// sseStateEvent is published when a URL changes state
+type sseStateEvent struct {
+	URL   string `json:"url"`
+	Name  string `json:"name"`
+	State string `json:"state"` // "found", "not_found", "unreachable", "recovered"
+}
+
+// sseIncidentEvent is published when a new incident is recorded
+type sseIncidentEvent struct {
+	URL         string `json:"url"`
+	Timestamp   string `json:"timestamp"`
+	EventType   string `json:"event_type"`
+	Description string `json:"description"`
+	Resolved    bool   `json:"resolved"`
+}
+
+// sseNotificationEvent is published for every email outcome
+type sseNotificationEvent struct {
+	Outcome     string   `json:"outcome"` // "sent" or "failed"
+	Type        string   `json:"type"`
+	Timestamp   string   `json:"timestamp"`
+	URL         string   `json:"url,omitempty"`
+	URLName     string   `json:"url_name,omitempty"`
+	Subject     string   `json:"subject,omitempty"`
+	Recipients  []string `json:"recipients,omitempty"`  // Configured recipients
+	Subscribers int      `json:"subscribers,omitempty"` // Number of self-service subscribers reached
+	Error       string   `json:"error,omitempty"`
+}
+
+// publishCheck publishes the outcome of a URL check
+func (m *Monitor) publishCheck(result URLCheckResult) {
+	if m.events == nil {
+		return
+	}
+
+	m.mu.RLock()
+	found := m.foundURLs[result.URL]
+	unreachable := m.unreachableURLs[result.URL]
+	lastCheck := m.perURLCheckTime[result.URL]
+	nextCheck := m.nextCheckTime[result.URL]
+	throttledUntil := m.throttledUntil[result.URL]
+	m.mu.RUnlock()
+
+	event := sseCheckEvent{
+		URL:         result.URL,
+		Name:        result.Name,
+		Found:       found,
+		Unreachable: unreachable,
+		LastCheck:   m.formatLocalTime(lastCheck),
+		NextCheck:   m.formatLocalTime(nextCheck),
+		ResponseMs:  result.ResponseTime.Milliseconds(),
+		StatusCode:  result.StatusCode,
+	}
+	if result.Error != nil {
+		event.Error = result.Error.Error()
+	}
+	if !throttledUntil.IsZero() {
+		event.Throttled = m.formatLocalTime(throttledUntil)
+	}
+	m.events.Publish("check", event)
+}
+
+// publishState publishes a URL state transition
+func (m *Monitor) publishState(result URLCheckResult, state string) {
+	m.events.Publish("state", sseStateEvent{
+		URL:   result.URL,
+		Name:  result.Name,
+		State: state,
+	})
+}
+
+// recordEvent adds an incident to the recent events buffer and publishes it
+func (m *Monitor) recordEvent(event EventRecord) {
+	m.recentEvents.Add(event)
+	m.events.Publish("incident", sseIncidentEvent{
+		URL:         event.URL,
+		Timestamp:   m.formatLocalTime(event.Timestamp),
+		EventType:   event.EventType,
+		Description: event.Message,
+		Resolved:    event.EventType == "not_found",
+	})
+}
+
+// handleEvents streams live dashboard updates as Server-Sent Events
+func (m *Monitor) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := m.events.Subscribe()
+	if ch == nil {
+		http.Error(w, "Too many live connections", http.StatusServiceUnavailable)
+		return
+	}
+	defer m.events.Unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
+
+	// Ask the browser to wait before reconnecting so reconnects stay within the rate limit
+	fmt.Fprintf(w, "retry: 10000\n\n")
+	flusher.Flush()
+
+	keepalive := time.NewTicker(sseKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case event := <-ch:
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepalive.C:
+			if _, err := fmt.Fprintf(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-m.stopChan:
+			return
+		}
+	}
+}
+
+// handleStatic serves files from the static/ directory (no directory listings)
+func (m *Monitor) handleStatic(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path[len("/static/"):])
+	filePath := "static" + name
+
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeFile(w, r, filePath)
+}
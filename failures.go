@@ -0,0 +1,93 @@
+package main
+
+import (
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+// maxRetryBackoff caps the delay between two attempts of the same check
+const maxRetryBackoff = time.Minute
+
+// isRetryable reports whether a failed attempt is worth repeating: no response at all, or a server error
+// A site that asked to wait (Retry-After) is not retried within the check
+func isRetryable(result URLCheckResult) bool {
+	return result.Error != nil && !result.Throttled && (result.StatusCode == 0 || result.StatusCode >= 500)
+}
+
+// retryBackoff returns the delay before a retry: the base doubled per attempt, with ±50% jitter
+func retryBackoff(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if delay > maxRetryBackoff || delay <= 0 {
+		delay = maxRetryBackoff
+	}
+	return delay/2 + rand.N(delay)
+}
+
+// checkURLWithRetries checks a URL, repeating attempts that failed without a usable response
+// Only the final attempt is returned; the retries it took are recorded in result.Retries
+// Waiting for a retry ends early when stop (the URL's check loop) or the service is stopped
+func (m *Monitor) checkURLWithRetries(urlConfig URLConfig, stop <-chan struct{}) URLCheckResult {
+	retries := m.getConfig().CheckRetries
+	base := time.Duration(m.getConfig().RetryBackoffSeconds) * time.Second
+
+	result := m.checkURL(urlConfig)
+	for attempt := 1; attempt <= retries && isRetryable(result); attempt++ {
+		delay := retryBackoff(base, attempt)
+		log.Printf("🔁 Check of %s failed (%v), retry %d/%d in %s", urlConfig.URL, result.Error, attempt, retries, delay.Round(time.Millisecond))
+
+		select {
+		case <-time.After(delay):
+		case <-m.stopChan:
+			return result
+		case <-stop:
+			return result
+		}
+
+		result = m.checkURL(urlConfig)
+		result.Retries = attempt
+	}
+	return result
+}
+
+// isStopped reports whether a stop channel has been closed (a nil channel never is)
+func isStopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// recordStateChange records a reachability change of a URL and updates its flapping status
+// It returns whether the URL is flapping: at least flap_threshold changes within flap_window_minutes
+// Callers hold m.mu
+func (m *Monitor) recordStateChange(url string, now time.Time) bool {
+	window := time.Duration(m.getConfig().FlapWindowMinutes) * time.Minute
+	cutoff := now.Add(-window)
+
+	changes := m.stateChanges[url][:0]
+	for _, t := range m.stateChanges[url] {
+		if t.After(cutoff) {
+			changes = append(changes, t)
+		}
+	}
+	changes = append(changes, now)
+	m.stateChanges[url] = changes
+
+	flapping := len(changes) >= m.getConfig().FlapThreshold
+	if flapping != m.flappingURLs[url] {
+		if flapping {
+			log.Printf("🔀 %s is flapping (%d state changes in %s), error and recovery emails suppressed", url, len(changes), window)
+		} else {
+			log.Printf("🔀 %s is no longer flapping", url)
+		}
+	}
+	if flapping {
+		m.flappingURLs[url] = true
+	} else {
+		delete(m.flappingURLs, url)
+	}
+	return flapping
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats, chosen by file extension
+const (
+	configFormatJSON = "json"
+	configFormatYAML = "yaml"
+	configFormatTOML = "toml"
+)
+
+// configFormat returns the format of a config file: .yaml/.yml and .toml are recognised, anything else is JSON
+func configFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return configFormatYAML
+	case ".toml":
+		return configFormatTOML
+	default:
+		return configFormatJSON
+	}
+}
+
+// decodeConfig strictly decodes a config file in the format matching its extension
+// Unknown settings (e.g. a misspelled "search_term") are rejected with their line number
+// It returns the top-level settings present in the file
+func decodeConfig(filename string, data []byte, config *Config) ([]string, error) {
+	switch configFormat(filename) {
+	case configFormatYAML:
+		return decodeYAMLConfig(data, config)
+	case configFormatTOML:
+		return decodeTOMLConfig(data, config)
+	default:
+		return decodeJSONConfig(data, config)
+	}
+}
+
+// decodeJSONConfig decodes a JSON config, rejecting unknown settings
+func decodeJSONConfig(data []byte, config *Config) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := checkJSONKeys(decoder, data, reflect.TypeOf(*config), ""); err != nil {
+		return nil, jsonErrorWithLine(data, err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, jsonErrorWithLine(data, err)
+	}
+
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(data, &present); err != nil {
+		return nil, err
+	}
+	settings := make([]string, 0, len(present))
+	for setting := range present {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// checkJSONKeys walks one JSON value and reports object keys that have no matching field in t
+// Values whose shape does not match t are left for json.Unmarshal to report
+func checkJSONKeys(decoder *json.Decoder, data []byte, t reflect.Type, path string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil // Scalar
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyToken.(string)
+
+			var valueType reflect.Type
+			if t != nil && t.Kind() == reflect.Struct {
+				field, found := jsonField(t, key)
+				if !found {
+					return fmt.Errorf("line %d: unknown setting %q%s", lineAt(data, decoder.InputOffset()), key, settingPath(path))
+				}
+				valueType = field.Type
+			} else if t != nil && t.Kind() == reflect.Map {
+				valueType = t.Elem()
+			}
+
+			if err := checkJSONKeys(decoder, data, valueType, strings.TrimPrefix(path+"."+key, ".")); err != nil {
+				return err
+			}
+		}
+	case '[':
+		var elemType reflect.Type
+		if t != nil && t.Kind() == reflect.Slice {
+			elemType = t.Elem()
+		}
+		for i := 0; decoder.More(); i++ {
+			if err := checkJSONKeys(decoder, data, elemType, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = decoder.Token() // Closing delimiter
+	return err
+}
+
+// jsonField finds the struct field decoded from the given JSON key
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if configFieldName(t.Field(i)) == key {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// settingPath formats the location of a nested setting for error messages
+func settingPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " in " + path
+}
+
+// lineAt returns the 1-based line of a byte offset
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// jsonErrorWithLine adds the line number to JSON syntax and type errors
+func jsonErrorWithLine(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("line %d: %v", lineAt(data, syntaxErr.Offset), err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("line %d: %v", lineAt(data, typeErr.Offset), err)
+	}
+	return err
+}
+
+// decodeYAMLConfig decodes a YAML config, rejecting unknown settings
+func decodeYAMLConfig(data []byte, config *Config) ([]string, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	var present map[string]any
+	if err := yaml.Unmarshal(data, &present); err != nil {
+		return nil, err
+	}
+	settings := make([]string, 0, len(present))
+	for setting := range present {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// decodeTOMLConfig decodes a TOML config, rejecting unknown settings
+func decodeTOMLConfig(data []byte, config *Config) ([]string, error) {
+	decoder := toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		var strictErr *toml.StrictMissingError
+		if errors.As(err, &strictErr) {
+			messages := make([]string, 0, len(strictErr.Errors))
+			for _, unknown := range strictErr.Errors {
+				row, _ := unknown.Position()
+				messages = append(messages, fmt.Sprintf("line %d: unknown setting %q", row, strings.Join(unknown.Key(), ".")))
+			}
+			return nil, errors.New(strings.Join(messages, "; "))
+		}
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			return nil, fmt.Errorf("line %d: %v", row, err)
+		}
+		return nil, err
+	}
+
+	var present map[string]any
+	if err := toml.Unmarshal(data, &present); err != nil {
+		return nil, err
+	}
+	settings := make([]string, 0, len(present))
+	for setting := range present {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
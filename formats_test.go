@@ -0,0 +1,184 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfigFormat(t *testing.T) {
+	tests := map[string]string{
+		"config.json":    configFormatJSON,
+		"config.yaml":    configFormatYAML,
+		"config.YML":     configFormatYAML,
+		"config.toml":    configFormatTOML,
+		"config":         configFormatJSON,
+		"config.conf":    configFormatJSON,
+		"/etc/x.y/c.yml": configFormatYAML,
+	}
+	for filename, want := range tests {
+		if got := configFormat(filename); got != want {
+			t.Errorf("configFormat(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
+
+func TestDecodeConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		filename     string
+		data         string
+		wantErr      []string // Substrings of the error; nil = success
+		wantSettings []string
+	}{
+		{
+			name:     "json valid",
+			filename: "config.json",
+			data: `{
+  "check_interval_seconds": 60,
+  "url_configs": [
+    {"url": "https://example.com", "search_terms": ["a"], "headers": {"X-Anything": "1"}}
+  ]
+}`,
+			wantSettings: []string{"check_interval_seconds", "url_configs"},
+		},
+		{
+			name:     "json unknown top-level",
+			filename: "config.json",
+			data: `{
+  "check_interval_seconds": 60,
+  "search_term": ["a"]
+}`,
+			wantErr: []string{"line 3", `unknown setting "search_term"`},
+		},
+		{
+			name:     "json unknown in url config",
+			filename: "config.json",
+			data: `{
+  "url_configs": [
+    {"url": "https://a.example"},
+    {"url": "https://b.example",
+     "serch_terms": ["a"]}
+  ]
+}`,
+			wantErr: []string{"line 5", `unknown setting "serch_terms" in url_configs[1]`},
+		},
+		{
+			name:     "json type error",
+			filename: "config.json",
+			data: `{
+  "check_interval_seconds": "60"
+}`,
+			wantErr: []string{"line 2"},
+		},
+		{
+			name:     "json syntax error",
+			filename: "config.json",
+			data: `{
+  "check_interval_seconds": 60
+  "time_offset_hours": 1
+}`,
+			wantErr: []string{"line 3"},
+		},
+		{
+			name:     "yaml valid",
+			filename: "config.yaml",
+			data: `check_interval_seconds: 60
+url_configs:
+  - url: https://example.com
+    search_terms: [a]
+    headers:
+      X-Anything: "1"
+`,
+			wantSettings: []string{"check_interval_seconds", "url_configs"},
+		},
+		{
+			name:     "yaml unknown top-level",
+			filename: "config.yaml",
+			data: `check_interval_seconds: 60
+search_term: [a]
+`,
+			wantErr: []string{"line 2", "search_term"},
+		},
+		{
+			name:     "yaml unknown in url config",
+			filename: "config.yaml",
+			data: `url_configs:
+  - url: https://example.com
+    serch_terms: [a]
+`,
+			wantErr: []string{"line 3", "serch_terms"},
+		},
+		{
+			name:         "yaml empty",
+			filename:     "config.yml",
+			data:         "",
+			wantSettings: []string{},
+		},
+		{
+			name:     "toml valid",
+			filename: "config.toml",
+			data: `check_interval_seconds = 60
+
+[[url_configs]]
+url = "https://example.com"
+search_terms = ["a"]
+headers = { X-Anything = "1" }
+`,
+			wantSettings: []string{"check_interval_seconds", "url_configs"},
+		},
+		{
+			name:     "toml unknown top-level",
+			filename: "config.toml",
+			data: `check_interval_seconds = 60
+search_term = ["a"]
+`,
+			wantErr: []string{"line 2", `unknown setting "search_term"`},
+		},
+		{
+			name:     "toml unknown in url config",
+			filename: "config.toml",
+			data: `[[url_configs]]
+url = "https://example.com"
+serch_terms = ["a"]
+`,
+			wantErr: []string{"line 3", `unknown setting "url_configs.serch_terms"`},
+		},
+		{
+			name:     "toml type error",
+			filename: "config.toml",
+			data: `check_interval_seconds = "60"
+`,
+			wantErr: []string{"line 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			settings, err := decodeConfig(tt.filename, []byte(tt.data), &config)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("decodeConfig succeeded, want error containing %q", tt.wantErr)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q does not contain %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeConfig: %v", err)
+			}
+			slices.Sort(settings)
+			if !reflect.DeepEqual(settings, tt.wantSettings) {
+				t.Errorf("settings = %q, want %q", settings, tt.wantSettings)
+			}
+			if len(tt.wantSettings) > 0 && config.CheckIntervalSeconds != 60 {
+				t.Errorf("check_interval_seconds = %d, want 60", config.CheckIntervalSeconds)
+			}
+		})
+	}
+}
@@ -3,13 +3,16 @@ module nestanak-info
 go 1.25
 
 require (
+	github.com/pelletier/go-toml/v2 v2.4.3
 	github.com/sendinblue/APIv3-go-library/v2 v2.1.2
 	golang.org/x/crypto v0.45.0
 	golang.org/x/net v0.47.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/antihax/optional v1.0.0 // indirect
 	golang.org/x/oauth2 v0.32.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
 )
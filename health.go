@@ -0,0 +1,249 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"time"
+)
+
+// stateSaveInterval is how often state is persisted by the maintenance ticker
+const stateSaveInterval = 5 * time.Minute
+
+// Health check statuses
+const (
+	healthOK       = "ok"
+	healthDegraded = "degraded"
+	healthFailing  = "failing"
+)
+
+// healthURL is the per-URL entry in /healthz and /readyz
+type healthURL struct {
+	URL         string     `json:"url"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	LastCheck   *time.Time `json:"last_check,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	Message     string     `json:"message,omitempty"`
+}
+
+// healthComponent is a single component in the /readyz breakdown
+type healthComponent struct {
+	Status      string     `json:"status"`
+	Message     string     `json:"message,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+}
+
+// healthResponse is the body of /healthz and /readyz
+type healthResponse struct {
+	Status     string                     `json:"status"`
+	CheckedAt  time.Time                  `json:"checked_at"`
+	Components map[string]healthComponent `json:"components,omitempty"`
+	URLs       []healthURL                `json:"urls"`
+}
+
+// healthStaleAfter is how long a URL may go without a check (or a successful
+// check) since last before it is reported: one missed interval plus the request timeout
+// For scheduled URLs the interval is the gap to the next scheduled run
+func (m *Monitor) healthStaleAfter(settings URLSettings, last time.Time) time.Duration {
+	interval := m.maxCheckInterval(settings) // Adaptive polling may wait longer than check_interval_seconds
+	if settings.schedule != nil {
+		next := m.nextScheduledCheck(settings, last)
+		if next.IsZero() {
+			return time.Duration(math.MaxInt64) // No further runs; never stale
+		}
+		interval = next.Sub(last)
+	}
+	return 2*interval + time.Duration(settings.ConnectTimeout)*time.Second
+}
+
+// checkURLHealth reports each URL goroutine's progress
+// With requireSuccess, a URL is also unhealthy if it has not been checked successfully recently
+func (m *Monitor) checkURLHealth(requireSuccess bool) ([]healthURL, bool) {
+	now := time.Now()
+	config := m.getConfig()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	healthy := true
+	urls := make([]healthURL, len(config.URLConfigs))
+	for i, urlConfig := range config.URLConfigs {
+		settings := config.urlSettings(urlConfig)
+		lastCheck := m.perURLCheckTime[urlConfig.URL]
+		lastSuccess := m.perURLSuccessTime[urlConfig.URL]
+
+		entry := healthURL{
+			URL:         urlConfig.URL,
+			Name:        urlConfig.Name,
+			Status:      healthOK,
+			LastCheck:   timePtr(lastCheck),
+			LastSuccess: timePtr(lastSuccess),
+		}
+
+		// Measure from startup until the first check/success happens
+		lastCheckRef := lastCheck
+		if lastCheckRef.IsZero() {
+			lastCheckRef = m.statsStartTime
+		}
+		lastSuccessRef := lastSuccess
+		if lastSuccessRef.IsZero() {
+			lastSuccessRef = m.statsStartTime
+		}
+
+		// A site that asked to wait is not checked until then
+		throttledUntil := m.throttledUntil[urlConfig.URL]
+		if throttledUntil.After(lastCheckRef) {
+			lastCheckRef = throttledUntil
+		}
+
+		checkStaleAfter := m.healthStaleAfter(settings, lastCheckRef)
+		successStaleAfter := m.healthStaleAfter(settings, lastSuccessRef)
+		switch {
+		case now.Sub(lastCheckRef) > checkStaleAfter:
+			entry.Status = healthFailing
+			entry.Message = "check loop has not completed a check within " + checkStaleAfter.String()
+		case requireSuccess && now.Sub(lastSuccessRef) > successStaleAfter:
+			entry.Status = healthDegraded
+			entry.Message = "no successful check within " + successStaleAfter.String()
+		case throttledUntil.After(now):
+			entry.Message = "site asked to wait until " + m.formatLocalTime(throttledUntil)
+		}
+
+		if entry.Status != healthOK {
+			healthy = false
+		}
+		urls[i] = entry
+	}
+
+	return urls, healthy
+}
+
+// checkStateHealth reports whether state is being persisted
+func (m *Monitor) checkStateHealth() healthComponent {
+	if m.state == nil || m.getConfig().StateFilePath == "" {
+		return healthComponent{Status: healthOK, Message: "state persistence disabled"}
+	}
+
+	m.mu.RLock()
+	lastSave := m.lastStateSave
+	lastError := m.lastStateSaveError
+	m.mu.RUnlock()
+
+	component := healthComponent{Status: healthOK, LastSuccess: timePtr(lastSave)}
+	if lastError != "" {
+		component.Status = healthFailing
+		component.Message = lastError
+		return component
+	}
+
+	// Saves happen at least every stateSaveInterval; allow one missed tick
+	lastSaveRef := lastSave
+	if lastSaveRef.IsZero() {
+		lastSaveRef = m.statsStartTime
+	}
+	if time.Since(lastSaveRef) > 2*stateSaveInterval {
+		component.Status = healthDegraded
+		component.Message = "state has not been saved within " + (2 * stateSaveInterval).String()
+	}
+	return component
+}
+
+// checkNotifierHealth reports the outcome of the most recent email delivery
+func (m *Monitor) checkNotifierHealth() healthComponent {
+	m.mu.RLock()
+	lastSuccess := m.lastEmailSuccess
+	lastFailure := m.lastEmailFailure
+	lastError := m.lastEmailError
+	m.mu.RUnlock()
+
+	component := healthComponent{
+		Status:      healthOK,
+		LastSuccess: timePtr(lastSuccess),
+		LastFailure: timePtr(lastFailure),
+	}
+	if lastSuccess.IsZero() && lastFailure.IsZero() {
+		component.Message = "no emails sent yet"
+	} else if lastFailure.After(lastSuccess) {
+		component.Status = healthDegraded
+		component.Message = lastError
+	}
+	return component
+}
+
+// checkUserAgentHealth reports the User-Agent list fetch status
+// A cached list is fine until it is older than user_agent_stale_days
+func (m *Monitor) checkUserAgentHealth() healthComponent {
+	status, fetchError, fetchedAt, poolFetchedAt := m.userAgentManager.GetFetchStatus()
+
+	component := healthComponent{Status: healthOK, Message: "fetch " + status}
+	switch status {
+	case "ok":
+		component.LastSuccess = timePtr(fetchedAt)
+	case "cached":
+		component.Message = "using cached User-Agent list fetched " + m.formatLocalTime(poolFetchedAt)
+		component.LastSuccess = timePtr(poolFetchedAt)
+		if fetchError != "" {
+			component.Message += " (last fetch failed: " + fetchError + ")"
+			component.LastFailure = timePtr(fetchedAt)
+		}
+		if time.Since(poolFetchedAt) > time.Duration(m.getConfig().UserAgentStaleDays)*24*time.Hour {
+			component.Status = healthDegraded
+		}
+	case "failed":
+		component.Status = healthDegraded
+		component.Message = "using fallback User-Agent: " + fetchError
+		component.LastFailure = timePtr(fetchedAt)
+	}
+	return component
+}
+
+// handleHealthz is the liveness probe: fails only if URL check loops are stuck
+func (m *Monitor) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	urls, healthy := m.checkURLHealth(false)
+
+	response := healthResponse{
+		Status:    healthOK,
+		CheckedAt: time.Now().UTC(),
+		URLs:      urls,
+	}
+
+	status := http.StatusOK
+	if !healthy {
+		response.Status = healthFailing
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, response)
+}
+
+// handleReadyz is the readiness probe: fails if any component is degraded
+func (m *Monitor) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	urls, urlsHealthy := m.checkURLHealth(true)
+
+	components := map[string]healthComponent{
+		"state":       m.checkStateHealth(),
+		"notifier":    m.checkNotifierHealth(),
+		"user_agents": m.checkUserAgentHealth(),
+	}
+
+	response := healthResponse{
+		Status:     healthOK,
+		CheckedAt:  time.Now().UTC(),
+		Components: components,
+		URLs:       urls,
+	}
+
+	healthy := urlsHealthy
+	for _, component := range components {
+		if component.Status != healthOK {
+			healthy = false
+		}
+	}
+
+	status := http.StatusOK
+	if !healthy {
+		response.Status = healthDegraded
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, response)
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// maxSnapshotBytes caps how much of a fetched page is kept in memory per URL
+const maxSnapshotBytes = 512 * 1024
+
+// maxMatchedRows caps the matched rows shown on the detail page
+const maxMatchedRows = 50
+
+// recordCheckHistory appends a check to the URL's history and keeps the page version
+func (m *Monitor) recordCheckHistory(result URLCheckResult) {
+	record := CheckRecord{
+		CheckedAt:    result.CheckedAt,
+		StatusCode:   result.StatusCode,
+		ResponseTime: result.ResponseTime,
+		Found:        result.Found,
+		Date:         result.Date,
+		Time:         result.Time,
+		Address:      result.Address,
+		Manual:       result.Manual,
+		Unchanged:    result.Unchanged,
+		SoftError:    result.SoftError,
+		SoftDetail:   result.SoftDetail,
+	}
+	if result.Error != nil {
+		record.Error = result.Error.Error()
+	}
+
+	// Build the snapshot outside the lock (HTML parsing); an unchanged page is not parsed again
+	var snapshot *URLSnapshot
+	if result.Body != "" && !result.Unchanged {
+		body := result.Body
+		truncated := false
+		if len(body) > maxSnapshotBytes {
+			body = strings.ToValidUTF8(body[:maxSnapshotBytes], "")
+			truncated = true
+		}
+		snapshot = &URLSnapshot{
+			FetchedAt:   result.CheckedAt,
+			StatusCode:  result.StatusCode,
+			Body:        body,
+			Text:        htmlToText(result.Body),
+			MatchedRows: findMatchingRows(result.Body, result.SearchTerms),
+			Truncated:   truncated,
+			Structure:   analyzeStructure(result.Body),
+		}
+	}
+
+	m.mu.Lock()
+
+	history := append(m.checkHistory[result.URL], record)
+	if len(history) > m.getConfig().CheckHistorySize {
+		history = history[len(history)-m.getConfig().CheckHistorySize:]
+	}
+	m.checkHistory[result.URL] = history
+
+	// Unchanged page: only move the fetch time of the latest version forward
+	if result.Unchanged {
+		if versions := m.snapshots[result.URL]; len(versions) > 0 {
+			refreshed := *versions[len(versions)-1]
+			refreshed.FetchedAt = result.CheckedAt
+			versions[len(versions)-1] = &refreshed
+		}
+	}
+
+	// Keep a new version only when the page changed; an unchanged page just refreshes the latest one
+	var previous *URLSnapshot
+	if snapshot != nil {
+		versions := m.snapshots[result.URL]
+		if n := len(versions); n > 0 && versions[n-1].Body == snapshot.Body {
+			versions[n-1] = snapshot
+		} else {
+			if n > 0 {
+				previous = versions[n-1]
+			}
+			versions = append(versions, snapshot)
+			if len(versions) > m.getConfig().SnapshotHistorySize {
+				// Copy so dropped bodies can be garbage collected
+				versions = append([]*URLSnapshot(nil), versions[len(versions)-m.getConfig().SnapshotHistorySize:]...)
+			}
+		}
+		m.snapshots[result.URL] = versions
+	}
+
+	m.mu.Unlock()
+
+	// A maintenance or empty page lacks the landmarks of the real page; that is reported as a soft error instead
+	if previous != nil && result.SoftError == "" {
+		m.handleStructureChange(result, previous.Structure, snapshot.Structure)
+	}
+}
+
+// getCheckHistory returns a URL's recent checks, newest first
+func (m *Monitor) getCheckHistory(url string) []CheckRecord {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := m.checkHistory[url]
+	records := make([]CheckRecord, len(history))
+	for i, record := range history {
+		records[len(history)-1-i] = record
+	}
+	return records
+}
+
+// getSnapshots returns a URL's kept page versions, newest first
+func (m *Monitor) getSnapshots(url string) []*URLSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	versions := m.snapshots[url]
+	snapshots := make([]*URLSnapshot, len(versions))
+	for i, snapshot := range versions {
+		snapshots[len(versions)-1-i] = snapshot
+	}
+	return snapshots
+}
+
+// htmlToText renders HTML as plain text: one line per block element, tabs between table cells
+func htmlToText(htmlContent string) string {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return ""
+	}
+
+	blockElements := map[string]bool{
+		"p": true, "div": true, "tr": true, "li": true, "br": true, "table": true,
+		"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"section": true, "article": true, "header": true, "footer": true, "ul": true, "ol": true,
+	}
+
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript", "head":
+				return
+			case "td", "th":
+				b.WriteString("\t")
+			}
+		}
+		if n.Type == html.TextNode {
+			text := strings.Join(strings.Fields(n.Data), " ")
+			if text != "" {
+				b.WriteString(text)
+				b.WriteString(" ")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && blockElements[n.Data] {
+			b.WriteString("\n")
+		}
+	}
+	walk(doc)
+
+	// Trim lines and collapse blank runs
+	lines := make([]string, 0)
+	for _, line := range strings.Split(b.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findMatchingRows returns table rows and text nodes that match the search terms
+// using the same term logic as containsAllSearchTerms
+func findMatchingRows(htmlContent string, searchTerms []string) []string {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	rows := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(text string) {
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" || seen[text] || len(rows) >= maxMatchedRows {
+			return
+		}
+		if containsAllSearchTerms(text, searchTerms) {
+			seen[text] = true
+			rows = append(rows, text)
+		}
+	}
+
+	// Table rows are matched as a whole (power pages), other text node by node (water pages)
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "head":
+				return
+			case "tr":
+				cells := make([]string, 0)
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+						cells = append(cells, strings.Join(strings.Fields(getTextContent(c)), " "))
+					}
+				}
+				add(strings.Join(cells, " | "))
+				return
+			}
+		}
+		if n.Type == html.TextNode {
+			add(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return rows
+}
+
+// handleURLDetail shows check history, matched rows, the last snapshot and page changes for one URL
+func (m *Monitor) handleURLDetail(w http.ResponseWriter, r *http.Request) {
+	urlConfig, exists := m.findURLConfig(r.PathValue("id"))
+	if !exists {
+		http.NotFound(w, r)
+		return
+	}
+
+	lang := m.uiLocale(w, r)
+
+	var status URLStatus
+	for _, s := range m.getURLStatuses() {
+		if s.URL == urlConfig.URL {
+			status = s
+			break
+		}
+	}
+
+	type checkRow struct {
+		CheckedAt    string
+		StatusCode   int
+		ResponseTime string
+		Found        bool
+		Date         string
+		TimeRange    string
+		Address      string
+		Error        string
+		Manual       bool
+		Unchanged    bool
+		SoftError    string
+		SoftDetail   string
+	}
+
+	history := m.getCheckHistory(urlConfig.URL)
+	rows := make([]checkRow, len(history))
+	for i, record := range history {
+		rows[i] = checkRow{
+			CheckedAt:    m.formatLocalTime(record.CheckedAt),
+			StatusCode:   record.StatusCode,
+			ResponseTime: record.ResponseTime.Round(time.Millisecond).String(),
+			Found:        record.Found,
+			Date:         record.Date,
+			TimeRange:    record.Time,
+			Address:      record.Address,
+			Error:        record.Error,
+			Manual:       record.Manual,
+			Unchanged:    record.Unchanged,
+			SoftError:    record.SoftError,
+			SoftDetail:   record.SoftDetail,
+		}
+	}
+
+	name := urlConfig.Name
+	if name == "" {
+		name = urlConfig.URL
+	}
+
+	type versionRow struct {
+		Index     int
+		FetchedAt string
+		Lines     int
+		Tables    int
+		Markers   int
+		Selected  bool
+		Diffable  bool // Has an older version to compare with
+	}
+
+	// ?diff=N compares version N (0 = newest) with the one before it
+	snapshots := m.getSnapshots(urlConfig.URL)
+	selected := 0
+	if v, err := strconv.Atoi(r.URL.Query().Get("diff")); err == nil && v >= 0 && v < len(snapshots)-1 {
+		selected = v
+	}
+
+	versions := make([]versionRow, len(snapshots))
+	for i, snapshot := range snapshots {
+		versions[i] = versionRow{
+			Index:     i,
+			FetchedAt: m.formatLocalTime(snapshot.FetchedAt),
+			Lines:     strings.Count(snapshot.Text, "\n") + 1,
+			Tables:    snapshot.Structure.Tables,
+			Markers:   len(snapshot.Structure.Markers),
+			Selected:  i == selected && len(snapshots) > 1,
+			Diffable:  i < len(snapshots)-1,
+		}
+	}
+
+	var diff []DiffLine
+	var diffFrom, diffTo string
+	if len(snapshots) > 1 {
+		diff = collapseDiff(diffLines(snapshots[selected+1].Text, snapshots[selected].Text))
+		diffFrom = versions[selected+1].FetchedAt
+		diffTo = versions[selected].FetchedAt
+	}
+
+	data := struct {
+		Lang        string
+		ID          string
+		Name        string
+		URL         string
+		SearchTerms []string
+		Status      URLStatus
+		History     []checkRow
+		Snapshot    *URLSnapshot
+		FetchedAt   string
+		HistorySize int
+		Versions    []versionRow
+		Diff        []DiffLine
+		DiffFrom    string
+		DiffTo      string
+		Throttled   string // Display time checks are paused until (Retry-After), empty if not throttled
+	}{
+		Lang:        lang,
+		ID:          urlID(urlConfig.URL),
+		Name:        name,
+		URL:         urlConfig.URL,
+		SearchTerms: urlConfig.SearchTerms,
+		Status:      status,
+		History:     rows,
+		HistorySize: m.getConfig().CheckHistorySize,
+		Versions:    versions,
+		Diff:        diff,
+		DiffFrom:    diffFrom,
+		DiffTo:      diffTo,
+	}
+	if len(snapshots) > 0 {
+		data.Snapshot = snapshots[0]
+		data.FetchedAt = versions[0].FetchedAt
+	}
+	if !status.Throttled.IsZero() {
+		data.Throttled = m.formatLocalTime(status.Throttled)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := m.templates.ExecuteTemplate(w, "url.html", data); err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		log.Printf("⚠️  Template error: %v", err)
+	}
+}
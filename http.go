@@ -6,6 +6,8 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -33,6 +35,7 @@ func (rl *HTTPRateLimiter) Allow(ip string) bool {
 
 	if len(validRequests) >= rl.limit {
 		rl.requests[ip] = validRequests
+		rl.rejected.Add(1)
 		return false
 	}
 
@@ -41,6 +44,14 @@ func (rl *HTTPRateLimiter) Allow(ip string) bool {
 	return true
 }
 
+// Rejected returns the number of requests rejected since startup
+func (rl *HTTPRateLimiter) Rejected() uint64 {
+	if rl == nil {
+		return 0
+	}
+	return rl.rejected.Load()
+}
+
 // Cleanup removes old IP entries
 func (rl *HTTPRateLimiter) Cleanup() {
 	if rl == nil {
@@ -69,7 +80,10 @@ func (rl *HTTPRateLimiter) Cleanup() {
 
 // initTemplates initializes HTML templates from disk
 func initTemplates() *template.Template {
-	tmpl, err := template.ParseGlob("templates/*.html")
+	funcs := template.FuncMap{
+		"t": translate,
+	}
+	tmpl, err := template.New("").Funcs(funcs).ParseGlob("templates/*.html")
 	if err != nil {
 		log.Fatalf("❌ Failed to load templates: %v", err)
 	}
@@ -111,6 +125,24 @@ func securityHeadersMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// crossOriginMiddleware rejects cross-site state-changing requests (CSRF protection)
+// Safe methods (GET, HEAD, OPTIONS) and non-browser clients are always allowed
+func crossOriginMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	protection := http.NewCrossOriginProtection()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := protection.Check(r); err != nil {
+			log.Printf("⚠️  Rejected cross-origin %s %s from %s", r.Method, r.URL.Path, getClientIP(r))
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeJSONError(w, http.StatusForbidden, "cross-origin request rejected")
+			} else {
+				http.Error(w, "Cross-origin request rejected", http.StatusForbidden)
+			}
+			return
+		}
+		next(w, r)
+	}
+}
+
 // rateLimitMiddleware wraps a handler with rate limiting
 func (m *Monitor) rateLimitMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -126,6 +158,35 @@ func (m *Monitor) rateLimitMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// settingLabel is one effective per-URL setting shown on the dashboard
+type settingLabel struct {
+	Text       string
+	Overridden bool // Set for this URL rather than inherited from the global settings
+}
+
+// urlSettingLabels describes the settings in effect for a URL
+func urlSettingLabels(lang string, settings URLSettings) []settingLabel {
+	schedule := settingLabel{translate(lang, "ui.setting.interval", settings.CheckIntervalSeconds), settings.Overridden["check_interval_seconds"]}
+	if settings.Schedule != "" {
+		schedule = settingLabel{translate(lang, "ui.setting.schedule", settings.Schedule), settings.Overridden["schedule"]}
+	}
+	labels := []settingLabel{
+		schedule,
+		{translate(lang, "ui.setting.timeout", settings.ConnectTimeout), settings.Overridden["connect_timeout"]},
+		{translate(lang, "ui.setting.cooldown", settings.AlertCooldownMinutes), settings.Overridden["alert_cooldown_minutes"]},
+		{translate(lang, "ui.setting.max_emails", settings.MaxEmailsPerDay), settings.Overridden["max_emails_per_day"]},
+		{translate(lang, "ui.setting.recipients", len(settings.Recipients)), settings.Overridden["recipients"]},
+	}
+
+	// Proxy by scheme and host only (no credentials)
+	if settings.Proxy == proxyDirect {
+		labels = append(labels, settingLabel{translate(lang, "ui.setting.no_proxy"), settings.Overridden["proxy"]})
+	} else if proxyURL, err := url.Parse(settings.Proxy); err == nil && settings.Proxy != "" {
+		labels = append(labels, settingLabel{translate(lang, "ui.setting.proxy", proxyURL.Scheme+"://"+proxyURL.Host), settings.Overridden["proxy"]})
+	}
+	return labels
+}
+
 // handleRoot handles the root endpoint
 func (m *Monitor) handleRoot(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
@@ -135,25 +196,34 @@ func (m *Monitor) handleRoot(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
+	lang := m.uiLocale(w, r)
 	uptime := time.Since(m.statsStartTime)
 	
-	// Get last check time and calculate next check
+	// Get last check time; the next check is the earliest one scheduled for any URL
 	m.mu.RLock()
 	lastCheck := m.lastCheckTime
 	m.mu.RUnlock()
+
+	statuses := m.getURLStatuses()
+	var nextCheck time.Time
+	for _, status := range statuses {
+		if !status.NextCheck.IsZero() && (nextCheck.IsZero() || status.NextCheck.Before(nextCheck)) {
+			nextCheck = status.NextCheck
+		}
+	}
 	
 	var lastCheckStr, nextCheckStr string
 	if !lastCheck.IsZero() {
 		lastCheckStr = m.formatLocalTime(lastCheck)
-		nextCheck := lastCheck.Add(time.Duration(m.config.CheckIntervalSeconds) * time.Second)
 		nextCheckStr = m.formatLocalTime(nextCheck)
 	} else {
-		lastCheckStr = "In progress..."
-		nextCheckStr = "After current check"
+		lastCheckStr = translate(lang, "ui.in_progress")
+		nextCheckStr = translate(lang, "ui.after_current_check")
 	}
 
 	// Build URL status list
 	type URLInfo struct {
+		ID            string
 		URL           string
 		Name          string
 		IsFound       bool
@@ -162,43 +232,55 @@ func (m *Monitor) handleRoot(w http.ResponseWriter, r *http.Request) {
 		SearchTerms   []string
 		LastCheck     string
 		NextCheck     string
+		Throttled     string // Display time checks are paused until (empty if not throttled)
+		Settings      []settingLabel
 	}
 
-	m.mu.RLock()
-	urlList := make([]URLInfo, len(m.config.URLConfigs))
-	for i, urlConfig := range m.config.URLConfigs {
-		shortURL := urlConfig.URL
-		if len(urlConfig.URL) > 60 {
-			shortURL = urlConfig.URL[:57] + "..."
+	urlList := make([]URLInfo, len(statuses))
+	for i, status := range statuses {
+		shortURL := status.URL
+		if len(status.URL) > 60 {
+			shortURL = status.URL[:57] + "..."
 		}
-		name := urlConfig.Name
+		name := status.Name
 		if name == "" {
 			name = shortURL
 		}
 		
 		// Get per-URL check time
 		var lastCheckStr, nextCheckStr string
-		if lastCheck, exists := m.perURLCheckTime[urlConfig.URL]; exists {
-			lastCheckStr = m.formatLocalTime(lastCheck)
-			nextCheck := lastCheck.Add(time.Duration(m.config.CheckIntervalSeconds) * time.Second)
-			nextCheckStr = m.formatLocalTime(nextCheck)
+		if !status.LastCheck.IsZero() {
+			lastCheckStr = m.formatLocalTime(status.LastCheck)
 		} else {
-			lastCheckStr = "Pending..."
-			nextCheckStr = "Soon..."
+			lastCheckStr = translate(lang, "ui.pending")
+		}
+		switch {
+		case status.LastCheck.IsZero() && status.NextCheck.IsZero():
+			nextCheckStr = translate(lang, "ui.soon")
+		case status.NextCheck.IsZero():
+			nextCheckStr = "—" // Schedule has no further runs
+		default:
+			nextCheckStr = m.formatLocalTime(status.NextCheck)
+		}
+		var throttledStr string
+		if !status.Throttled.IsZero() {
+			throttledStr = m.formatLocalTime(status.Throttled)
 		}
 		
 		urlList[i] = URLInfo{
-			URL:           urlConfig.URL,
+			ID:            status.ID,
+			URL:           status.URL,
 			Name:          name,
-			IsFound:       m.foundURLs[urlConfig.URL],
-			IsUnreachable: m.unreachableURLs[urlConfig.URL],
+			IsFound:       status.Found,
+			IsUnreachable: status.Unreachable,
 			ShortURL:      shortURL,
-			SearchTerms:   urlConfig.SearchTerms,
+			SearchTerms:   status.SearchTerms,
 			LastCheck:     lastCheckStr,
 			NextCheck:     nextCheckStr,
+			Throttled:     throttledStr,
+			Settings:      urlSettingLabels(lang, status.Settings),
 		}
 	}
-	m.mu.RUnlock()
 
 	// Get recent matches
 	matches := m.getRecentMatches()
@@ -207,6 +289,8 @@ func (m *Monitor) handleRoot(w http.ResponseWriter, r *http.Request) {
 	emailNotifications := m.getRecentEmailNotifications(20)
 
 	data := struct {
+		Lang               string
+		Locales            []string
 		URLCount           int
 		Uptime             string
 		Interval           int
@@ -219,17 +303,19 @@ func (m *Monitor) handleRoot(w http.ResponseWriter, r *http.Request) {
 		MatchesHours       int
 		MaxEmailsPerDay    int
 	}{
-		URLCount:           len(m.config.URLConfigs),
+		Lang:               lang,
+		Locales:            supportedLocales,
+		URLCount:           len(urlList),
 		Uptime:             formatDuration(uptime),
-		Interval:           m.config.CheckIntervalSeconds,
+		Interval:           m.getConfig().CheckIntervalSeconds,
 		Timestamp:          m.formatLocalTime(time.Now()),
 		LastCheck:          lastCheckStr,
 		NextCheck:          nextCheckStr,
 		URLs:               urlList,
 		RecentMatches:      matches,
 		EmailNotifications: emailNotifications,
-		MatchesHours:       m.config.RecentMatchesHours,
-		MaxEmailsPerDay:    m.config.MaxEmailsPerURLPerDay,
+		MatchesHours:       m.getConfig().RecentMatchesHours,
+		MaxEmailsPerDay:    m.getConfig().MaxEmailsPerURLPerDay,
 	}
 
 	if err := m.templates.ExecuteTemplate(w, "root.html", data); err != nil {
@@ -238,7 +324,37 @@ func (m *Monitor) handleRoot(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleStatus handles the status endpoint
+// handleCheckNow handles the dashboard "check now" buttons (form field "id" is a URL ID or "all")
+func (m *Monitor) handleCheckNow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+	if id == "all" {
+		for _, urlConfig := range m.getURLConfigs() {
+			if err := m.triggerCheck(urlConfig); err != nil {
+				log.Printf("ℹ️  Manual check of %s not scheduled: %v", urlConfig.URL, err)
+			}
+		}
+	} else {
+		urlConfig, exists := m.findURLConfig(id)
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		if err := m.triggerCheck(urlConfig); err != nil {
+			log.Printf("ℹ️  Manual check of %s not scheduled: %v", urlConfig.URL, err)
+		}
+	}
+
+	// Results arrive on the dashboard via the live event stream
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// handleStatus handles the status endpoint (static "OK"; see /healthz and /readyz for real checks)
 func (m *Monitor) handleStatus(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
 	fmt.Fprintf(w, "OK\n")
@@ -246,17 +362,44 @@ func (m *Monitor) handleStatus(w http.ResponseWriter, r *http.Request) {
 
 // startHTTPServer starts the HTTP server
 func (m *Monitor) startHTTPServer() {
-	if !m.config.HTTPEnabled {
+	if !m.getConfig().HTTPEnabled {
 		return
 	}
 
 	// Public routes (no auth required, with security headers)
 	http.HandleFunc("/status", securityHeadersMiddleware(m.handleStatus))
+	http.HandleFunc("/healthz", securityHeadersMiddleware(m.handleHealthz))
+	http.HandleFunc("/readyz", securityHeadersMiddleware(m.handleReadyz))
 	http.HandleFunc("/login", securityHeadersMiddleware(m.handleLogin))
 	http.HandleFunc("/logout", securityHeadersMiddleware(m.handleLogout))
 
+	// Public self-service subscription routes (rate limited, no auth)
+	if m.getConfig().SubscriptionsEnabled {
+		http.HandleFunc("/subscribe", securityHeadersMiddleware(m.rateLimitMiddleware(m.handleSubscribe)))
+		http.HandleFunc("/subscribe/confirm", securityHeadersMiddleware(m.rateLimitMiddleware(m.handleSubscribeConfirm)))
+		http.HandleFunc("/unsubscribe", securityHeadersMiddleware(m.rateLimitMiddleware(m.handleUnsubscribe)))
+	}
+
+	// Static assets (dashboard script)
+	http.HandleFunc("/static/", securityHeadersMiddleware(m.handleStatic))
+
 	// Protected routes (require auth if enabled, with security headers)
 	http.HandleFunc("/", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleRoot))))
+	http.HandleFunc("/events", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleEvents))))
+	http.HandleFunc("/check", securityHeadersMiddleware(crossOriginMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleCheckNow)))))
+	http.HandleFunc("/url/{id}", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleURLDetail))))
+	http.HandleFunc("/admin/urls", securityHeadersMiddleware(crossOriginMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAdminURLs)))))
+	http.HandleFunc("/admin/urls/{id}", securityHeadersMiddleware(crossOriginMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAdminURLUpdate)))))
+	http.HandleFunc("/admin/urls/{id}/delete", securityHeadersMiddleware(crossOriginMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAdminURLDelete)))))
+
+	// JSON API (same auth and rate limiting as the web interface)
+	http.HandleFunc("/api/v1/urls", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAPIURLs))))
+	http.HandleFunc("/api/v1/urls/check", securityHeadersMiddleware(crossOriginMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAPICheckAll)))))
+	http.HandleFunc("/api/v1/urls/{id}/check", securityHeadersMiddleware(crossOriginMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAPICheckURL)))))
+	http.HandleFunc("/api/v1/incidents", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAPIIncidents))))
+	http.HandleFunc("/api/v1/notifications", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAPINotifications))))
+	http.HandleFunc("/api/v1/state/stats", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAPIStateStats))))
+	http.HandleFunc("/api/", securityHeadersMiddleware(m.rateLimitMiddleware(m.AuthMiddleware(m.handleAPINotFound))))
 
 	if m.httpRateLimiter != nil {
 		go func() {
@@ -269,12 +412,12 @@ func (m *Monitor) startHTTPServer() {
 	}
 
 	go func() {
-		log.Printf("🌐 Starting HTTP server on %s", m.config.HTTPListen)
-		m.addLog(fmt.Sprintf("Starting HTTP server on %s", m.config.HTTPListen))
+		log.Printf("🌐 Starting HTTP server on %s", m.getConfig().HTTPListen)
+		m.addLog(fmt.Sprintf("Starting HTTP server on %s", m.getConfig().HTTPListen))
 
-		if err := http.ListenAndServe(m.config.HTTPListen, nil); err != nil {
+		if err := http.ListenAndServe(m.getConfig().HTTPListen, nil); err != nil {
 			// Fatal error - exit so systemd can restart the service (network may not be ready)
-			log.Fatalf("❌ Failed to start HTTP server on %s: %v", m.config.HTTPListen, err)
+			log.Fatalf("❌ Failed to start HTTP server on %s: %v", m.getConfig().HTTPListen, err)
 		}
 	}()
 }
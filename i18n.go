@@ -0,0 +1,602 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Supported locales
+const (
+	LocaleSrLatn = "sr-Latn" // Serbian, Latin script
+	LocaleSrCyrl = "sr-Cyrl" // Serbian, Cyrillic script
+	LocaleEn     = "en"      // English
+)
+
+// supportedLocales lists locales in the order they are offered in the web UI
+var supportedLocales = []string{LocaleSrLatn, LocaleSrCyrl, LocaleEn}
+
+// messageCatalog holds all user-facing text, keyed by locale and message key
+// Values are fmt format strings; arguments are supplied by the caller
+var messageCatalog = map[string]map[string]string{
+	LocaleSrLatn: {
+		// Match notifications
+		"email.water_planned.subject":        "💧 Planirana isključenja vode - %s",
+		"email.water_planned.subject_nodate": "💧 Planirana isključenja vode u Batajnici",
+		"email.water_planned.body":           "Planirana isključenja vode u Batajnici:\n\n%s\n\nVreme: %s\n\nLokacije - %s",
+		"email.water_malfunction.subject":    "💧 KVAR - Nema vode u Batajnici",
+		"email.water_malfunction.body":       "Trenutno nema vode na sledećim lokacijama:\n\n%s\n\nProcenjeno vreme popravke: %s\n\nZa više informacija: https://www.bvk.rs/kvarovi-na-mrezi/",
+		"email.power.subject":                "⚡ Neće biti struje u Batajnici - %s",
+		"email.power.subject_nodate":         "⚡ Planirano isključenje struje u Batajnici",
+		"email.power.body":                   "Neće biti struje u Batajnici:\n\n%s\n\nVreme: %s h\n\nNa adresama - %s",
+
+		// Connection notifications
+		"email.error.subject":     "🔴 Nestanak-Info - Greška u povezivanju: %s",
+		"email.error.body":        "Otkrivena greška u povezivanju\n\nNaziv URL-a: %s\nURL: %s\n\nDetalji greške:\n%v\n\nVreme: %s\n\nOvaj URL trenutno nije dostupan. Dobićete obaveštenje kada veza bude ponovo uspostavljena.",
+		"email.recovery.subject":  "🟢 Nestanak-Info - Veza ponovo uspostavljena: %s",
+		"email.recovery.body":     "Veza ponovo uspostavljena\n\nNaziv URL-a: %s\nURL: %s\n\nTrajanje prekida: %s\nVraćeno u: %s\n\nURL je ponovo dostupan i praćenje je nastavljeno.",
+		"email.structure.subject": "🧩 Nestanak-Info - Promenjena struktura stranice: %s",
+		"email.structure.body":    "Promenjena struktura stranice\n\nNaziv URL-a: %s\nURL: %s\n\nNestali su delovi stranice na koje se oslanja izdvajanje podataka:\n%s\n\nVreme: %s\n\nProverite stranicu sa detaljima URL-a (razlike između verzija) i po potrebi ažurirajte izdvajanje.",
+		"email.structure.tables":  "Tabele: %d → %d",
+		"email.structure.marker":  "Nedostaje oznaka odeljka: %q",
+
+		// Web UI
+		"ui.subtitle":               "Sistem za praćenje nestanka struje i vode",
+		"ui.service_online":         "● Servis radi",
+		"ui.urls_monitored":         "Praćeni URL-ovi",
+		"ui.uptime":                 "Vreme rada servisa",
+		"ui.check_interval":         "Interval provere",
+		"ui.seconds":                "%d sekundi",
+		"ui.last_check":             "Poslednja provera",
+		"ui.next_check":             "Sledeća provera",
+		"ui.email_limit":            "Limit mejlova po URL-u",
+		"ui.per_day":                "%d/dan",
+		"ui.monitored_urls":         "Praćeni URL-ovi",
+		"ui.last":                   "🕐 Poslednja: %s",
+		"ui.next":                   "⏰ Sledeća: %s",
+		"ui.badge.unreachable":      "🔴 NEDOSTUPNO",
+		"ui.badge.found":            "⚠️ PRONAĐENO",
+		"ui.badge.ok":               "✓ OK",
+		"ui.recent_matches":         "Nedavna poklapanja (poslednjih %d sati)",
+		"ui.resolved":               "✓ Rešeno",
+		"ui.ongoing":                "⚠ U toku",
+		"ui.email_notifications":    "📧 Nedavna obaveštenja mejlom",
+		"ui.type.match":             "🔔 Poklapanje",
+		"ui.type.error":             "⚠️ Greška",
+		"ui.type.recovery":          "✅ Oporavak",
+		"ui.type.structure":         "🧩 Struktura",
+		"ui.sent_to":                "📧 Poslato:",
+		"ui.sent_to_subscribers":    "+ %v pretplatnika",
+		"ui.server_time":            "Vreme servera: %s",
+		"ui.in_progress":            "U toku...",
+		"ui.after_current_check":    "Posle tekuće provere",
+		"ui.pending":                "Na čekanju...",
+		"ui.soon":                   "Uskoro...",
+		"ui.live.connected":         "⚡ Uživo",
+		"ui.live.reconnecting":      "⏸ Ponovno povezivanje...",
+		"ui.check_now":              "🔄 Proveri sada",
+		"ui.check_all":              "🔄 Proveri sve",
+		"ui.detail.back":            "← Kontrolna tabla",
+		"ui.detail.view":            "🔍 Detalji",
+		"ui.detail.search_terms":    "Termini pretrage",
+		"ui.detail.history":         "Istorija provera (poslednjih %d)",
+		"ui.detail.no_history":      "Još nema provera.",
+		"ui.detail.col.time":        "Vreme provere",
+		"ui.detail.col.status":      "HTTP",
+		"ui.detail.col.response":    "Odziv",
+		"ui.detail.col.found":       "Pronađeno",
+		"ui.detail.col.date":        "Datum",
+		"ui.detail.col.time_range":  "Vreme",
+		"ui.detail.col.address":     "Adresa",
+		"ui.detail.col.error":       "Greška",
+		"ui.detail.manual":          "ručno",
+		"ui.detail.unchanged":       "bez promene",
+		"ui.detail.unchanged_hint":  "Stranica se nije promenila od prethodne provere; rezultat je ponovo iskorišćen",
+		"ui.soft_error.hint":        "Sajt je odgovorio, ali ne pravom stranicom; prethodni rezultat je zadržan",
+		"ui.soft_error.maintenance": "🚧 stranica održavanja",
+		"ui.soft_error.portal":      "🚧 portal / prijava",
+		"ui.soft_error.empty":       "🚧 prazna stranica",
+		"ui.throttled":              "⏸️ Sajt traži pauzu do %s",
+		"ui.throttled.hint":         "Sajt je odgovorio 429/503 sa Retry-After; provere su pauzirane i to se ne računa kao prekid",
+		"ui.detail.matched_rows":    "Redovi koji se poklapaju",
+		"ui.detail.no_matched_rows": "Nijedan red u poslednjem snimku ne sadrži termine pretrage.",
+		"ui.detail.snapshot_text":   "Poslednji snimak stranice (tekst)",
+		"ui.detail.snapshot_raw":    "Poslednji snimak stranice (HTML)",
+		"ui.detail.fetched":         "Preuzeto %s (HTTP %d)",
+		"ui.detail.truncated":       "skraćeno",
+		"ui.detail.no_snapshot":     "Stranica još nije preuzeta.",
+		"ui.detail.changes":         "Promene stranice",
+		"ui.detail.no_changes":      "Još nema promena. Nova verzija se čuva kada se sadržaj stranice promeni.",
+		"ui.detail.version":         "%s — %d redova, %d tabela, %d oznaka",
+		"ui.detail.diff":            "Razlike: %s → %s",
+		"ui.detail.no_text_changes": "HTML je promenjen, ali je vidljivi tekst isti.",
+		"ui.detail.skip":            "… %s nepromenjenih redova",
+		"ui.admin.title":            "Upravljanje URL-ovima",
+		"ui.admin.link":             "⚙️ Upravljaj URL-ovima",
+		"ui.admin.note":             "Izmene se proveravaju, upisuju u config.json i primenjuju odmah, bez restartovanja.",
+		"ui.admin.add":              "Dodaj URL",
+		"ui.admin.name":             "Naziv",
+		"ui.admin.url":              "URL",
+		"ui.admin.search_terms":     "Termini pretrage (jedan po redu)",
+		"ui.admin.extractor":        "Izdvajanje detalja",
+		"ui.admin.extractor.auto":   "Automatski (prema URL-u)",
+		"ui.admin.extractor.power":  "Struja (tabele)",
+		"ui.admin.extractor.water":  "Voda (BVK tekst)",
+		"ui.admin.save":             "💾 Sačuvaj",
+		"ui.admin.add_button":       "➕ Dodaj",
+		"ui.admin.delete":           "🗑️ Obriši",
+		"ui.admin.confirm_delete":   "Potvrđujem brisanje",
+		"ui.admin.error":            "Izmene nisu sačuvane:",
+		"ui.admin.saved.added":      "URL je dodat i praćenje je pokrenuto.",
+		"ui.admin.saved.updated":    "URL je izmenjen i praćenje je ponovo pokrenuto.",
+		"ui.admin.saved.deleted":    "URL je uklonjen i praćenje je zaustavljeno.",
+		"ui.admin.overrides":        "Podešavanja za ovaj URL (opciono)",
+		"ui.admin.overrides_note":   "Ostavite prazno za globalnu vrednost (prikazana sivo).",
+		"ui.admin.check_interval":   "Interval provere (sekunde)",
+		"ui.admin.connect_timeout":  "Tajmaut (sekunde)",
+		"ui.admin.alert_cooldown":   "Pauza između upozorenja (minuti)",
+		"ui.admin.max_emails":       "Najviše mejlova dnevno",
+		"ui.admin.recipients":       "Primaoci (jedan po redu, zamenjuju globalnu listu)",
+		"ui.admin.recipients_hint":  "%d globalnih primalaca",
+		"ui.setting.interval":       "svakih %d s",
+		"ui.setting.timeout":        "tajmaut %d s",
+		"ui.setting.cooldown":       "pauza %d min",
+		"ui.setting.max_emails":     "%d mejlova/dan",
+		"ui.setting.recipients":     "%d primalaca",
+		"ui.setting.overridden":     "Podešeno posebno za ovaj URL",
+		"ui.admin.schedule":         "Raspored (cron ili vremenski prozori; zamenjuje interval)",
+		"ui.setting.schedule":       "raspored: %s",
+		"ui.setting.proxy":          "proksi %s",
+		"ui.setting.no_proxy":       "bez proksija",
+		"ui.admin.proxy":            "Proksi (http://, https://, socks5:// ili direct za direktnu vezu)",
+		"ui.admin.request_options":  "Opcije zahteva",
+		"ui.admin.headers":          "Dodatna zaglavlja (jedno \"Ime: vrednost\" po redu)",
+		"ui.admin.cookie_jar":       "Čuvaj kolačiće koje sajt postavi između provera",
+		"ui.admin.ca_file":          "Dodatni CA sertifikati (PEM fajl na serveru)",
+		"ui.admin.pinned_cert":      "SHA-256 otisak dozvoljenog sertifikata (zamenjuje proveru lanca)",
+		"ui.admin.tls_min_version":  "Minimalna TLS verzija",
+		"ui.admin.tls_default":      "podrazumevano (TLS 1.2)",
+		"ui.login.title":            "Potrebna prijava",
+		"ui.login.subtitle":         "Unesite lozinku za pristup servisu Nestanak-Info",
+		"ui.login.password":         "Lozinka",
+		"ui.login.submit":           "🔓 Prijava",
+		"ui.login.locked_out":       "Previše neuspešnih pokušaja. Pokušajte ponovo kasnije.",
+		"ui.login.invalid":          "Pogrešna lozinka",
+
+		// Subscriptions
+		"email.unsubscribe_footer":  "\n\n--\nOdjava sa obaveštenja: %s",
+		"email.confirm.subject":     "Nestanak-Info - Potvrdite prijavu na obaveštenja",
+		"email.confirm.body":        "Zatražili ste obaveštenja o nestanku struje i vode za: %s\n\nPotvrdite prijavu otvaranjem ovog linka:\n%s\n\nLink važi %d sati. Ako niste vi zatražili prijavu, zanemarite ovu poruku.",
+		"subscribe.all_areas":       "sva područja",
+		"subscribe.title":           "Prijava na obaveštenja",
+		"subscribe.subtitle":        "Dobijajte mejl kada je u vašem kraju najavljen nestanak struje ili vode",
+		"subscribe.email":           "Email adresa",
+		"subscribe.terms":           "Područja (ulice, naselja)",
+		"subscribe.terms_hint":      "Razdvojite zarezom, npr. Batajnica, Šangajska. Ostavite prazno za sva obaveštenja.",
+		"subscribe.language":        "Jezik obaveštenja",
+		"subscribe.submit":          "📬 Prijavi se",
+		"subscribe.check_email":     "Poslali smo link za potvrdu na %s. Prijava je aktivna tek kada ga otvorite.",
+		"subscribe.confirmed":       "Prijava je potvrđena za %s.",
+		"subscribe.unsubscribe":     "Odjaviti %s sa svih obaveštenja?",
+		"subscribe.unsubscribe_btn": "Odjavi me",
+		"subscribe.unsubscribed":    "%s više neće dobijati obaveštenja.",
+		"subscribe.invalid":         "Link je nevažeći ili je istekao.",
+		"subscribe.error.email":     "Unesite ispravnu email adresu.",
+		"subscribe.error.terms":     "Unesite najviše %d područja, svako kraće od 100 znakova.",
+		"subscribe.error.busy":      "Servis je trenutno zauzet. Pokušajte ponovo kasnije.",
+		"subscribe.error.send":      "Slanje mejla za potvrdu nije uspelo. Pokušajte ponovo kasnije.",
+	},
+	LocaleSrCyrl: {
+		// Match notifications
+		"email.water_planned.subject":        "💧 Планирана искључења воде - %s",
+		"email.water_planned.subject_nodate": "💧 Планирана искључења воде у Батајници",
+		"email.water_planned.body":           "Планирана искључења воде у Батајници:\n\n%s\n\nВреме: %s\n\nЛокације - %s",
+		"email.water_malfunction.subject":    "💧 КВАР - Нема воде у Батајници",
+		"email.water_malfunction.body":       "Тренутно нема воде на следећим локацијама:\n\n%s\n\nПроцењено време поправке: %s\n\nЗа више информација: https://www.bvk.rs/kvarovi-na-mrezi/",
+		"email.power.subject":                "⚡ Неће бити струје у Батајници - %s",
+		"email.power.subject_nodate":         "⚡ Планирано искључење струје у Батајници",
+		"email.power.body":                   "Неће бити струје у Батајници:\n\n%s\n\nВреме: %s h\n\nНа адресама - %s",
+
+		// Connection notifications
+		"email.error.subject":     "🔴 Nestanak-Info - Грешка у повезивању: %s",
+		"email.error.body":        "Откривена грешка у повезивању\n\nНазив URL-а: %s\nURL: %s\n\nДетаљи грешке:\n%v\n\nВреме: %s\n\nОвај URL тренутно није доступан. Добићете обавештење када веза буде поново успостављена.",
+		"email.recovery.subject":  "🟢 Nestanak-Info - Веза поново успостављена: %s",
+		"email.recovery.body":     "Веза поново успостављена\n\nНазив URL-а: %s\nURL: %s\n\nТрајање прекида: %s\nВраћено у: %s\n\nURL је поново доступан и праћење је настављено.",
+		"email.structure.subject": "🧩 Nestanak-Info - Промењена структура странице: %s",
+		"email.structure.body":    "Промењена структура странице\n\nНазив URL-а: %s\nURL: %s\n\nНестали су делови странице на које се ослања издвајање података:\n%s\n\nВреме: %s\n\nПроверите страницу са детаљима URL-а (разлике између верзија) и по потреби ажурирајте издвајање.",
+		"email.structure.tables":  "Табеле: %d → %d",
+		"email.structure.marker":  "Недостаје ознака одељка: %q",
+
+		// Web UI
+		"ui.subtitle":               "Систем за праћење нестанка струје и воде",
+		"ui.service_online":         "● Сервис ради",
+		"ui.urls_monitored":         "Праћени URL-ови",
+		"ui.uptime":                 "Време рада сервиса",
+		"ui.check_interval":         "Интервал провере",
+		"ui.seconds":                "%d секунди",
+		"ui.last_check":             "Последња провера",
+		"ui.next_check":             "Следећа провера",
+		"ui.email_limit":            "Лимит мејлова по URL-у",
+		"ui.per_day":                "%d/дан",
+		"ui.monitored_urls":         "Праћени URL-ови",
+		"ui.last":                   "🕐 Последња: %s",
+		"ui.next":                   "⏰ Следећа: %s",
+		"ui.badge.unreachable":      "🔴 НЕДОСТУПНО",
+		"ui.badge.found":            "⚠️ ПРОНАЂЕНО",
+		"ui.badge.ok":               "✓ ОК",
+		"ui.recent_matches":         "Недавна поклапања (последњих %d сати)",
+		"ui.resolved":               "✓ Решено",
+		"ui.ongoing":                "⚠ У току",
+		"ui.email_notifications":    "📧 Недавна обавештења мејлом",
+		"ui.type.match":             "🔔 Поклапање",
+		"ui.type.error":             "⚠️ Грешка",
+		"ui.type.recovery":          "✅ Опоравак",
+		"ui.type.structure":         "🧩 Структура",
+		"ui.sent_to":                "📧 Послато:",
+		"ui.sent_to_subscribers":    "+ %v претплатника",
+		"ui.server_time":            "Време сервера: %s",
+		"ui.in_progress":            "У току...",
+		"ui.after_current_check":    "После текуће провере",
+		"ui.pending":                "На чекању...",
+		"ui.soon":                   "Ускоро...",
+		"ui.live.connected":         "⚡ Уживо",
+		"ui.live.reconnecting":      "⏸ Поновно повезивање...",
+		"ui.check_now":              "🔄 Провери сада",
+		"ui.check_all":              "🔄 Провери све",
+		"ui.detail.back":            "← Контролна табла",
+		"ui.detail.view":            "🔍 Детаљи",
+		"ui.detail.search_terms":    "Термини претраге",
+		"ui.detail.history":         "Историја провера (последњих %d)",
+		"ui.detail.no_history":      "Још нема провера.",
+		"ui.detail.col.time":        "Време провере",
+		"ui.detail.col.status":      "HTTP",
+		"ui.detail.col.response":    "Одзив",
+		"ui.detail.col.found":       "Пронађено",
+		"ui.detail.col.date":        "Датум",
+		"ui.detail.col.time_range":  "Време",
+		"ui.detail.col.address":     "Адреса",
+		"ui.detail.col.error":       "Грешка",
+		"ui.detail.manual":          "ручно",
+		"ui.detail.unchanged":       "без промене",
+		"ui.detail.unchanged_hint":  "Страница се није променила од претходне провере; резултат је поново искоришћен",
+		"ui.soft_error.hint":        "Сајт је одговорио, али не правом страницом; претходни резултат је задржан",
+		"ui.soft_error.maintenance": "🚧 страница одржавања",
+		"ui.soft_error.portal":      "🚧 портал / пријава",
+		"ui.soft_error.empty":       "🚧 празна страница",
+		"ui.throttled":              "⏸️ Сајт тражи паузу до %s",
+		"ui.throttled.hint":         "Сајт је одговорио 429/503 са Retry-After; провере су паузиране и то се не рачуна као прекид",
+		"ui.detail.matched_rows":    "Редови који се поклапају",
+		"ui.detail.no_matched_rows": "Ниједан ред у последњем снимку не садржи термине претраге.",
+		"ui.detail.snapshot_text":   "Последњи снимак странице (текст)",
+		"ui.detail.snapshot_raw":    "Последњи снимак странице (HTML)",
+		"ui.detail.fetched":         "Преузето %s (HTTP %d)",
+		"ui.detail.truncated":       "скраћено",
+		"ui.detail.no_snapshot":     "Страница још није преузета.",
+		"ui.detail.changes":         "Промене странице",
+		"ui.detail.no_changes":      "Још нема промена. Нова верзија се чува када се садржај странице промени.",
+		"ui.detail.version":         "%s — %d редова, %d табела, %d ознака",
+		"ui.detail.diff":            "Разлике: %s → %s",
+		"ui.detail.no_text_changes": "HTML је промењен, али је видљиви текст исти.",
+		"ui.detail.skip":            "… %s непромењених редова",
+		"ui.admin.title":            "Управљање URL-овима",
+		"ui.admin.link":             "⚙️ Управљај URL-овима",
+		"ui.admin.note":             "Измене се проверавају, уписују у config.json и примењују одмах, без рестартовања.",
+		"ui.admin.add":              "Додај URL",
+		"ui.admin.name":             "Назив",
+		"ui.admin.url":              "URL",
+		"ui.admin.search_terms":     "Термини претраге (један по реду)",
+		"ui.admin.extractor":        "Издвајање детаља",
+		"ui.admin.extractor.auto":   "Аутоматски (према URL-у)",
+		"ui.admin.extractor.power":  "Струја (табеле)",
+		"ui.admin.extractor.water":  "Вода (БВК текст)",
+		"ui.admin.save":             "💾 Сачувај",
+		"ui.admin.add_button":       "➕ Додај",
+		"ui.admin.delete":           "🗑️ Обриши",
+		"ui.admin.confirm_delete":   "Потврђујем брисање",
+		"ui.admin.error":            "Измене нису сачуване:",
+		"ui.admin.saved.added":      "URL је додат и праћење је покренуто.",
+		"ui.admin.saved.updated":    "URL је измењен и праћење је поново покренуто.",
+		"ui.admin.saved.deleted":    "URL је уклоњен и праћење је заустављено.",
+		"ui.admin.overrides":        "Подешавања за овај URL (опционо)",
+		"ui.admin.overrides_note":   "Оставите празно за глобалну вредност (приказана сиво).",
+		"ui.admin.check_interval":   "Интервал провере (секунде)",
+		"ui.admin.connect_timeout":  "Тајмаут (секунде)",
+		"ui.admin.alert_cooldown":   "Пауза између упозорења (минути)",
+		"ui.admin.max_emails":       "Највише мејлова дневно",
+		"ui.admin.recipients":       "Примаоци (један по реду, замењују глобалну листу)",
+		"ui.admin.recipients_hint":  "%d глобалних прималаца",
+		"ui.setting.interval":       "сваких %d с",
+		"ui.setting.timeout":        "тајмаут %d с",
+		"ui.setting.cooldown":       "пауза %d мин",
+		"ui.setting.max_emails":     "%d мејлова/дан",
+		"ui.setting.recipients":     "%d прималаца",
+		"ui.setting.overridden":     "Подешено посебно за овај URL",
+		"ui.admin.schedule":         "Распоред (cron или временски прозори; замењује интервал)",
+		"ui.setting.schedule":       "распоред: %s",
+		"ui.setting.proxy":          "прокси %s",
+		"ui.setting.no_proxy":       "без проксија",
+		"ui.admin.proxy":            "Прокси (http://, https://, socks5:// или direct за директну везу)",
+		"ui.admin.request_options":  "Опције захтева",
+		"ui.admin.headers":          "Додатна заглавља (једно \"Име: вредност\" по реду)",
+		"ui.admin.cookie_jar":       "Чувај колачиће које сајт постави између провера",
+		"ui.admin.ca_file":          "Додатни CA сертификати (PEM фајл на серверу)",
+		"ui.admin.pinned_cert":      "SHA-256 отисак дозвољеног сертификата (замењује проверу ланца)",
+		"ui.admin.tls_min_version":  "Минимална TLS верзија",
+		"ui.admin.tls_default":      "подразумевано (TLS 1.2)",
+		"ui.login.title":            "Потребна пријава",
+		"ui.login.subtitle":         "Унесите лозинку за приступ сервису Nestanak-Info",
+		"ui.login.password":         "Лозинка",
+		"ui.login.submit":           "🔓 Пријава",
+		"ui.login.locked_out":       "Превише неуспешних покушаја. Покушајте поново касније.",
+		"ui.login.invalid":          "Погрешна лозинка",
+
+		// Subscriptions
+		"email.unsubscribe_footer":  "\n\n--\nОдјава са обавештења: %s",
+		"email.confirm.subject":     "Nestanak-Info - Потврдите пријаву на обавештења",
+		"email.confirm.body":        "Затражили сте обавештења о нестанку струје и воде за: %s\n\nПотврдите пријаву отварањем овог линка:\n%s\n\nЛинк важи %d сати. Ако нисте ви затражили пријаву, занемарите ову поруку.",
+		"subscribe.all_areas":       "сва подручја",
+		"subscribe.title":           "Пријава на обавештења",
+		"subscribe.subtitle":        "Добијајте мејл када је у вашем крају најављен нестанак струје или воде",
+		"subscribe.email":           "Email адреса",
+		"subscribe.terms":           "Подручја (улице, насеља)",
+		"subscribe.terms_hint":      "Раздвојите зарезом, нпр. Батајница, Шангајска. Оставите празно за сва обавештења.",
+		"subscribe.language":        "Језик обавештења",
+		"subscribe.submit":          "📬 Пријави се",
+		"subscribe.check_email":     "Послали смо линк за потврду на %s. Пријава је активна тек када га отворите.",
+		"subscribe.confirmed":       "Пријава је потврђена за %s.",
+		"subscribe.unsubscribe":     "Одјавити %s са свих обавештења?",
+		"subscribe.unsubscribe_btn": "Одјави ме",
+		"subscribe.unsubscribed":    "%s више неће добијати обавештења.",
+		"subscribe.invalid":         "Линк је неважећи или је истекао.",
+		"subscribe.error.email":     "Унесите исправну email адресу.",
+		"subscribe.error.terms":     "Унесите највише %d подручја, свако краће од 100 знакова.",
+		"subscribe.error.busy":      "Сервис је тренутно заузет. Покушајте поново касније.",
+		"subscribe.error.send":      "Слање мејла за потврду није успело. Покушајте поново касније.",
+	},
+	LocaleEn: {
+		// Match notifications
+		"email.water_planned.subject":        "💧 Planned water outage - %s",
+		"email.water_planned.subject_nodate": "💧 Planned water outage in Batajnica",
+		"email.water_planned.body":           "Planned water outage in Batajnica:\n\n%s\n\nTime: %s\n\nLocations - %s",
+		"email.water_malfunction.subject":    "💧 FAULT - No water in Batajnica",
+		"email.water_malfunction.body":       "There is currently no water at the following locations:\n\n%s\n\nEstimated repair time: %s\n\nMore information: https://www.bvk.rs/kvarovi-na-mrezi/",
+		"email.power.subject":                "⚡ No power in Batajnica - %s",
+		"email.power.subject_nodate":         "⚡ Planned power outage in Batajnica",
+		"email.power.body":                   "There will be no power in Batajnica:\n\n%s\n\nTime: %s h\n\nAt addresses - %s",
+
+		// Connection notifications
+		"email.error.subject":     "🔴 Nestanak-Info - Connection Error: %s",
+		"email.error.body":        "Connection Error Detected\n\nURL Name: %s\nURL: %s\n\nError Details:\n%v\n\nTimestamp: %s\n\nThis URL is currently unreachable. You will receive a recovery notification when the connection is restored.",
+		"email.recovery.subject":  "🟢 Nestanak-Info - Connection Restored: %s",
+		"email.recovery.body":     "Connection Restored\n\nURL Name: %s\nURL: %s\n\nDowntime Duration: %s\nRestored At: %s\n\nThe URL is now reachable again and monitoring has resumed.",
+		"email.structure.subject": "🧩 Nestanak-Info - Page Structure Changed: %s",
+		"email.structure.body":    "Page Structure Changed\n\nURL Name: %s\nURL: %s\n\nParts of the page that data extraction relies on have disappeared:\n%s\n\nTimestamp: %s\n\nCheck the URL detail page (diff between versions) and update the extraction if needed.",
+		"email.structure.tables":  "Tables: %d → %d",
+		"email.structure.marker":  "Missing section marker: %q",
+
+		// Web UI
+		"ui.subtitle":               "Power & Water Outage Monitoring System",
+		"ui.service_online":         "● Service Online",
+		"ui.urls_monitored":         "URLs Monitored",
+		"ui.uptime":                 "Service Uptime",
+		"ui.check_interval":         "Check Interval",
+		"ui.seconds":                "%d seconds",
+		"ui.last_check":             "Last Check",
+		"ui.next_check":             "Next Check",
+		"ui.email_limit":            "Email Limit per URL",
+		"ui.per_day":                "%d/day",
+		"ui.monitored_urls":         "Monitored URLs",
+		"ui.last":                   "🕐 Last: %s",
+		"ui.next":                   "⏰ Next: %s",
+		"ui.badge.unreachable":      "🔴 UNREACHABLE",
+		"ui.badge.found":            "⚠️ FOUND",
+		"ui.badge.ok":               "✓ OK",
+		"ui.recent_matches":         "Recent Matches (Last %d hours)",
+		"ui.resolved":               "✓ Resolved",
+		"ui.ongoing":                "⚠ Ongoing",
+		"ui.email_notifications":    "📧 Recent Email Notifications",
+		"ui.type.match":             "🔔 Match",
+		"ui.type.error":             "⚠️ Error",
+		"ui.type.recovery":          "✅ Recovery",
+		"ui.type.structure":         "🧩 Structure",
+		"ui.sent_to":                "📧 Sent to:",
+		"ui.sent_to_subscribers":    "+ %v subscribers",
+		"ui.server_time":            "Server time: %s",
+		"ui.in_progress":            "In progress...",
+		"ui.after_current_check":    "After current check",
+		"ui.pending":                "Pending...",
+		"ui.soon":                   "Soon...",
+		"ui.live.connected":         "⚡ Live",
+		"ui.live.reconnecting":      "⏸ Reconnecting...",
+		"ui.check_now":              "🔄 Check now",
+		"ui.check_all":              "🔄 Check all",
+		"ui.detail.back":            "← Dashboard",
+		"ui.detail.view":            "🔍 Details",
+		"ui.detail.search_terms":    "Search terms",
+		"ui.detail.history":         "Check history (last %d)",
+		"ui.detail.no_history":      "No checks yet.",
+		"ui.detail.col.time":        "Checked",
+		"ui.detail.col.status":      "HTTP",
+		"ui.detail.col.response":    "Response",
+		"ui.detail.col.found":       "Found",
+		"ui.detail.col.date":        "Date",
+		"ui.detail.col.time_range":  "Time",
+		"ui.detail.col.address":     "Address",
+		"ui.detail.col.error":       "Error",
+		"ui.detail.manual":          "manual",
+		"ui.detail.unchanged":       "unchanged",
+		"ui.detail.unchanged_hint":  "The page has not changed since the previous check; its result was reused",
+		"ui.soft_error.hint":        "The site answered, but not with the real page; the previous result was kept",
+		"ui.soft_error.maintenance": "🚧 maintenance page",
+		"ui.soft_error.portal":      "🚧 portal / login page",
+		"ui.soft_error.empty":       "🚧 empty page",
+		"ui.throttled":              "⏸️ Site asked to wait until %s",
+		"ui.throttled.hint":         "The site answered 429/503 with Retry-After; checks are paused and this is not counted as an outage",
+		"ui.detail.matched_rows":    "Matched rows",
+		"ui.detail.no_matched_rows": "No rows in the last snapshot matched the search terms.",
+		"ui.detail.snapshot_text":   "Last page snapshot (text)",
+		"ui.detail.snapshot_raw":    "Last page snapshot (raw HTML)",
+		"ui.detail.fetched":         "Fetched %s (HTTP %d)",
+		"ui.detail.truncated":       "truncated",
+		"ui.detail.no_snapshot":     "No page fetched yet.",
+		"ui.detail.changes":         "Page changes",
+		"ui.detail.no_changes":      "No changes seen yet. A new version is kept whenever the page content changes.",
+		"ui.detail.version":         "%s — %d lines, %d tables, %d markers",
+		"ui.detail.diff":            "Diff: %s → %s",
+		"ui.detail.no_text_changes": "The HTML changed but the visible text is the same.",
+		"ui.detail.skip":            "… %s unchanged lines",
+		"ui.admin.title":            "Manage URLs",
+		"ui.admin.link":             "⚙️ Manage URLs",
+		"ui.admin.note":             "Changes are validated, written to config.json and applied immediately without a restart.",
+		"ui.admin.add":              "Add URL",
+		"ui.admin.name":             "Name",
+		"ui.admin.url":              "URL",
+		"ui.admin.search_terms":     "Search terms (one per line)",
+		"ui.admin.extractor":        "Detail extractor",
+		"ui.admin.extractor.auto":   "Automatic (by URL)",
+		"ui.admin.extractor.power":  "Power (tables)",
+		"ui.admin.extractor.water":  "Water (BVK text)",
+		"ui.admin.save":             "💾 Save",
+		"ui.admin.add_button":       "➕ Add",
+		"ui.admin.delete":           "🗑️ Delete",
+		"ui.admin.confirm_delete":   "Confirm removal",
+		"ui.admin.error":            "Changes were not saved:",
+		"ui.admin.saved.added":      "URL added and monitoring started.",
+		"ui.admin.saved.updated":    "URL updated and its monitoring restarted.",
+		"ui.admin.saved.deleted":    "URL removed and monitoring stopped.",
+		"ui.admin.overrides":        "Per-URL overrides (optional)",
+		"ui.admin.overrides_note":   "Leave empty to use the global value (shown in grey).",
+		"ui.admin.check_interval":   "Check interval (seconds)",
+		"ui.admin.connect_timeout":  "Timeout (seconds)",
+		"ui.admin.alert_cooldown":   "Alert cooldown (minutes)",
+		"ui.admin.max_emails":       "Max emails per day",
+		"ui.admin.recipients":       "Recipients (one per line, replaces the global list)",
+		"ui.admin.recipients_hint":  "%d global recipients",
+		"ui.setting.interval":       "every %ds",
+		"ui.setting.timeout":        "timeout %ds",
+		"ui.setting.cooldown":       "cooldown %d min",
+		"ui.setting.max_emails":     "%d emails/day",
+		"ui.setting.recipients":     "%d recipients",
+		"ui.setting.overridden":     "Overridden for this URL",
+		"ui.admin.schedule":         "Schedule (cron or time windows; replaces the interval)",
+		"ui.setting.schedule":       "schedule: %s",
+		"ui.setting.proxy":          "proxy %s",
+		"ui.setting.no_proxy":       "no proxy",
+		"ui.admin.proxy":            "Proxy (http://, https://, socks5://, or direct to bypass the global proxy)",
+		"ui.admin.request_options":  "Request options",
+		"ui.admin.headers":          "Extra headers (one \"Name: value\" per line)",
+		"ui.admin.cookie_jar":       "Keep cookies set by the site between checks",
+		"ui.admin.ca_file":          "Extra CA certificates (PEM file on the server)",
+		"ui.admin.pinned_cert":      "SHA-256 fingerprint of the accepted certificate (replaces chain verification)",
+		"ui.admin.tls_min_version":  "Minimum TLS version",
+		"ui.admin.tls_default":      "default (TLS 1.2)",
+		"ui.login.title":            "Login Required",
+		"ui.login.subtitle":         "Enter password to access Nestanak-Info",
+		"ui.login.password":         "Password",
+		"ui.login.submit":           "🔓 Login",
+		"ui.login.locked_out":       "Too many failed attempts. Please try again later.",
+		"ui.login.invalid":          "Invalid password",
+
+		// Subscriptions
+		"email.unsubscribe_footer":  "\n\n--\nUnsubscribe from these alerts: %s",
+		"email.confirm.subject":     "Nestanak-Info - Confirm your alert subscription",
+		"email.confirm.body":        "You asked to receive power and water outage alerts for: %s\n\nConfirm your subscription by opening this link:\n%s\n\nThe link is valid for %d hours. If you did not request this, ignore this message.",
+		"subscribe.all_areas":       "all areas",
+		"subscribe.title":           "Subscribe to Alerts",
+		"subscribe.subtitle":        "Get an email when a power or water outage is announced for your area",
+		"subscribe.email":           "Email address",
+		"subscribe.terms":           "Areas (streets, settlements)",
+		"subscribe.terms_hint":      "Separate with commas, e.g. Batajnica, Šangajska. Leave empty for all alerts.",
+		"subscribe.language":        "Alert language",
+		"subscribe.submit":          "📬 Subscribe",
+		"subscribe.check_email":     "We sent a confirmation link to %s. The subscription becomes active once you open it.",
+		"subscribe.confirmed":       "Subscription confirmed for %s.",
+		"subscribe.unsubscribe":     "Unsubscribe %s from all alerts?",
+		"subscribe.unsubscribe_btn": "Unsubscribe",
+		"subscribe.unsubscribed":    "%s will no longer receive alerts.",
+		"subscribe.invalid":         "This link is invalid or has expired.",
+		"subscribe.error.email":     "Please enter a valid email address.",
+		"subscribe.error.terms":     "Enter at most %d areas, each shorter than 100 characters.",
+		"subscribe.error.busy":      "The service is busy right now. Please try again later.",
+		"subscribe.error.send":      "Sending the confirmation email failed. Please try again later.",
+	},
+}
+
+// normalizeLocale maps a user-supplied language tag to a supported locale
+// Returns empty string if the tag is not supported
+func normalizeLocale(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	switch {
+	case tag == "sr-latn" || tag == "sr" || strings.HasPrefix(tag, "sr-latn-"):
+		return LocaleSrLatn
+	case tag == "sr-cyrl" || strings.HasPrefix(tag, "sr-cyrl-"):
+		return LocaleSrCyrl
+	case tag == "en" || strings.HasPrefix(tag, "en-"):
+		return LocaleEn
+	}
+	return ""
+}
+
+// localeUsesLatin reports whether text for this locale should be written in Latin script
+func localeUsesLatin(locale string) bool {
+	return locale != LocaleSrCyrl
+}
+
+// localizeExtracted converts extracted page text (always Cyrillic on the source sites)
+// to the script preferred by the locale
+func localizeExtracted(locale, text string) string {
+	if localeUsesLatin(locale) {
+		return cyrillicToLatin(text)
+	}
+	return text
+}
+
+// translate returns the message for key in the given locale, formatted with args
+// Falls back to English, then to the key itself, if the message is missing
+func translate(locale, key string, args ...interface{}) string {
+	format, ok := messageCatalog[locale][key]
+	if !ok {
+		format, ok = messageCatalog[LocaleEn][key]
+		if !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// recipientLocale returns the preferred locale for an email recipient
+func (m *Monitor) recipientLocale(email string) string {
+	for addr, lang := range m.getConfig().RecipientLanguages {
+		if strings.EqualFold(addr, email) {
+			if locale := normalizeLocale(lang); locale != "" {
+				return locale
+			}
+		}
+	}
+	return m.getConfig().DefaultLanguage
+}
+
+// uiLocale determines the web UI locale from the ?lang= parameter, the language cookie,
+// or the configured UI language (in that order)
+func (m *Monitor) uiLocale(w http.ResponseWriter, r *http.Request) string {
+	if locale := normalizeLocale(r.URL.Query().Get("lang")); locale != "" {
+		http.SetCookie(w, &http.Cookie{
+			Name:     "nestanak_lang",
+			Value:    locale,
+			Path:     "/",
+			MaxAge:   365 * 24 * 60 * 60,
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+		return locale
+	}
+	if cookie, err := r.Cookie("nestanak_lang"); err == nil {
+		if locale := normalizeLocale(cookie.Value); locale != "" {
+			return locale
+		}
+	}
+	return m.getConfig().UILanguage
+}
@@ -94,6 +94,11 @@ echo -e "${GREEN}📁 Copying HTML templates...${NC}"
 mkdir -p "$INSTALL_DIR/templates"
 cp templates/*.html "$INSTALL_DIR/templates/" 2>/dev/null || true
 
+# Copy static assets (dashboard live updates)
+echo -e "${GREEN}📁 Copying static assets...${NC}"
+mkdir -p "$INSTALL_DIR/static"
+cp static/*.js "$INSTALL_DIR/static/" 2>/dev/null || true
+
 # Restore existing config if this was an update
 if [ "$UPDATE_MODE" = true ] && [ -n "$TEMP_CONFIG" ] && [ -f "$TEMP_CONFIG" ]; then
     cp "$TEMP_CONFIG" "$INSTALL_DIR/config.json"
@@ -141,8 +146,8 @@ echo "Waiting for network interface to be ready..."
 # Extract HTTP bind address from config
 CONFIG_FILE="$1"
 if [ -f "$CONFIG_FILE" ]; then
-    # Parse HTTP address from config.json (handles both "ip:port" and ":port")
-    HTTP_ADDR=$(grep -oP '"http_listen"\s*:\s*"\K[^"]+' "$CONFIG_FILE" 2>/dev/null || echo "")
+    # Parse HTTP address from the JSON, YAML or TOML config (handles both "ip:port" and ":port")
+    HTTP_ADDR=$(grep -oP '"?http_listen"?\s*[:=]\s*"?\K[^"\s#]+' "$CONFIG_FILE" 2>/dev/null | head -n 1 || echo "")
     
     if [ -n "$HTTP_ADDR" ]; then
         # Extract IP (everything before last colon, or empty if starts with :)
@@ -210,7 +215,8 @@ WorkingDirectory=$INSTALL_DIR
 # Wait for network interface before starting
 ExecStartPre=$INSTALL_DIR/wait-for-network.sh $INSTALL_DIR/config.json
 
-ExecStart=$INSTALL_DIR/nestanak-info
+ExecStart=$INSTALL_DIR/nestanak-info -config $INSTALL_DIR/config.json
+ExecReload=/bin/kill -HUP \$MAINPID
 
 # Auto-restart if network wasn't ready (will retry every 15 seconds)
 Restart=always
@@ -14,6 +14,8 @@ import (
 func main() {
 	// CLI flags
 	setPassword := flag.Bool("set-password", false, "Generate Argon2id hash for a new password")
+	configPath := flag.String("config", defaultConfigPath, "Path to the configuration file")
+	showConfig := flag.Bool("dump-config", false, "Print the effective configuration and the source of each value (secrets redacted), then exit")
 	flag.Parse()
 	
 	// Handle password generation
@@ -22,10 +24,16 @@ func main() {
 		return
 	}
 	
+	// Handle configuration dump
+	if *showConfig {
+		handleConfigDump(*configPath)
+		return
+	}
+	
 	log.Printf("🎯 Nestanak-Info Service Starting...")
 	
 	// Load configuration
-	config, err := loadConfig("config.json")
+	config, err := loadConfig(*configPath)
 	if err != nil {
 		log.Fatalf("❌ Failed to load configuration: %v", err)
 	}
@@ -38,7 +46,7 @@ func main() {
 	log.Printf("✅ Configuration loaded and validated successfully")
 	
 	// Initialize and start monitor
-	monitor := NewMonitor(config)
+	monitor := NewMonitor(config, *configPath)
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -47,6 +55,16 @@ func main() {
 	// Start monitor in background
 	go monitor.Start()
 	
+	// Reload configuration on SIGHUP (systemctl reload)
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			log.Printf("📨 SIGHUP received, reloading configuration...")
+			monitor.reloadConfig("SIGHUP")
+		}
+	}()
+	
 	// Wait for shutdown signal
 	<-sigChan
 	log.Printf("👋 Shutting down gracefully...")
@@ -58,6 +76,25 @@ func main() {
 	os.Exit(0)
 }
 
+// handleConfigDump prints the effective configuration and exits non-zero if it is invalid
+func handleConfigDump(configPath string) {
+	config, sources, err := loadConfigWithSources(configPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	
+	fmt.Printf("📄 Effective configuration (%s)\n", configPath)
+	fmt.Println()
+	dumpConfig(os.Stdout, &config, sources)
+	
+	if err := ValidateConfig(config); err != nil {
+		fmt.Println()
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
 // handlePasswordGeneration generates an Argon2id hash for a password
 func handlePasswordGeneration() {
 	fmt.Println("🔐 Password Hash Generator")
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metricsDurationBuckets are the upper bounds (seconds) of the check duration histogram
+var metricsDurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// NewMetrics creates an empty metrics collector
+func NewMetrics() *Metrics {
+	return &Metrics{
+		checks:       make(map[string]*CheckMetrics),
+		emailsSent:   make(map[string]uint64),
+		emailsFailed: make(map[string]uint64),
+	}
+}
+
+// ObserveCheck records the duration and outcome of a URL check
+func (mt *Metrics) ObserveCheck(result URLCheckResult) {
+	if mt == nil {
+		return
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	cm, exists := mt.checks[result.URL]
+	if !exists {
+		cm = &CheckMetrics{
+			DurationBuckets: make([]uint64, len(metricsDurationBuckets)),
+			StatusCodes:     make(map[int]uint64),
+			SoftErrors:      make(map[string]uint64),
+		}
+		mt.checks[result.URL] = cm
+	}
+
+	seconds := result.ResponseTime.Seconds()
+	for i, bound := range metricsDurationBuckets {
+		if seconds <= bound {
+			cm.DurationBuckets[i]++
+		}
+	}
+	cm.DurationSum += seconds
+	cm.DurationCount++
+
+	if result.StatusCode > 0 {
+		cm.StatusCodes[result.StatusCode]++
+	} else if result.Error != nil {
+		cm.Errors++
+	}
+	if result.Unchanged {
+		cm.Unchanged++
+	}
+	cm.Retries += uint64(result.Retries)
+	if result.SoftError != "" {
+		cm.SoftErrors[result.SoftError]++
+	}
+}
+
+// RecordEmail records a delivery attempt for the given email type
+func (mt *Metrics) RecordEmail(emailType string, err error) {
+	if mt == nil {
+		return
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	if err != nil {
+		mt.emailsFailed[emailType]++
+	} else {
+		mt.emailsSent[emailType]++
+	}
+}
+
+// escapeLabelValue escapes a Prometheus label value
+func escapeLabelValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+// formatFloat formats a sample value the way Prometheus expects
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// writeMetricHeader writes the HELP and TYPE lines for a metric family
+func writeMetricHeader(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// sortedKeys returns map keys in sorted order for stable output
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// renderMetrics renders all metrics in the Prometheus text exposition format
+func (m *Monitor) renderMetrics() string {
+	var b strings.Builder
+
+	// Per-URL state gauges (from the live monitor state)
+	statuses := m.getURLStatuses()
+
+	writeMetricHeader(&b, "nestanak_url_match", "gauge", "Whether the search terms are currently found on the URL (1 = found).")
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "nestanak_url_match{url=\"%s\",name=\"%s\"} %d\n",
+			escapeLabelValue(status.URL), escapeLabelValue(status.Name), boolToInt(status.Found))
+	}
+
+	writeMetricHeader(&b, "nestanak_url_unreachable", "gauge", "Whether the URL is currently unreachable (1 = down).")
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "nestanak_url_unreachable{url=\"%s\",name=\"%s\"} %d\n",
+			escapeLabelValue(status.URL), escapeLabelValue(status.Name), boolToInt(status.Unreachable))
+	}
+
+	writeMetricHeader(&b, "nestanak_url_throttled", "gauge", "Whether checks of the URL are paused because the site asked to wait (429/503 with Retry-After).")
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "nestanak_url_throttled{url=\"%s\",name=\"%s\"} %d\n",
+			escapeLabelValue(status.URL), escapeLabelValue(status.Name), boolToInt(!status.Throttled.IsZero()))
+	}
+
+	writeMetricHeader(&b, "nestanak_url_last_check_timestamp_seconds", "gauge", "Unix time of the last completed check.")
+	for _, status := range statuses {
+		if status.LastCheck.IsZero() {
+			continue
+		}
+		fmt.Fprintf(&b, "nestanak_url_last_check_timestamp_seconds{url=\"%s\"} %d\n",
+			escapeLabelValue(status.URL), status.LastCheck.Unix())
+	}
+
+	// Check counters and histogram
+	m.metrics.mu.Lock()
+	urls := sortedKeys(m.metrics.checks)
+
+	writeMetricHeader(&b, "nestanak_check_duration_seconds", "histogram", "Duration of URL checks (URLCheckResult.ResponseTime).")
+	for _, url := range urls {
+		cm := m.metrics.checks[url]
+		label := escapeLabelValue(url)
+		for i, bound := range metricsDurationBuckets {
+			fmt.Fprintf(&b, "nestanak_check_duration_seconds_bucket{url=\"%s\",le=\"%s\"} %d\n", label, formatFloat(bound), cm.DurationBuckets[i])
+		}
+		fmt.Fprintf(&b, "nestanak_check_duration_seconds_bucket{url=\"%s\",le=\"+Inf\"} %d\n", label, cm.DurationCount)
+		fmt.Fprintf(&b, "nestanak_check_duration_seconds_sum{url=\"%s\"} %s\n", label, formatFloat(cm.DurationSum))
+		fmt.Fprintf(&b, "nestanak_check_duration_seconds_count{url=\"%s\"} %d\n", label, cm.DurationCount)
+	}
+
+	writeMetricHeader(&b, "nestanak_http_responses_total", "counter", "HTTP responses received from monitored URLs by status code.")
+	for _, url := range urls {
+		cm := m.metrics.checks[url]
+		codes := make([]int, 0, len(cm.StatusCodes))
+		for code := range cm.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(&b, "nestanak_http_responses_total{url=\"%s\",code=\"%d\"} %d\n", escapeLabelValue(url), code, cm.StatusCodes[code])
+		}
+	}
+
+	writeMetricHeader(&b, "nestanak_check_errors_total", "counter", "Checks that failed without an HTTP response (DNS, connect, timeout).")
+	for _, url := range urls {
+		fmt.Fprintf(&b, "nestanak_check_errors_total{url=\"%s\"} %d\n", escapeLabelValue(url), m.metrics.checks[url].Errors)
+	}
+
+	writeMetricHeader(&b, "nestanak_checks_unchanged_total", "counter", "Checks that found the page unchanged (HTTP 304 or same body) and reused the previous result.")
+	for _, url := range urls {
+		fmt.Fprintf(&b, "nestanak_checks_unchanged_total{url=\"%s\"} %d\n", escapeLabelValue(url), m.metrics.checks[url].Unchanged)
+	}
+
+	writeMetricHeader(&b, "nestanak_check_retries_total", "counter", "Check attempts repeated after a failure without a usable response.")
+	for _, url := range urls {
+		fmt.Fprintf(&b, "nestanak_check_retries_total{url=\"%s\"} %d\n", escapeLabelValue(url), m.metrics.checks[url].Retries)
+	}
+
+	writeMetricHeader(&b, "nestanak_check_soft_errors_total", "counter", "Checks that got a page which is not the monitored content, by kind (maintenance, portal, empty).")
+	for _, url := range urls {
+		softErrors := m.metrics.checks[url].SoftErrors
+		for _, kind := range sortedKeys(softErrors) {
+			fmt.Fprintf(&b, "nestanak_check_soft_errors_total{url=\"%s\",kind=\"%s\"} %d\n", escapeLabelValue(url), kind, softErrors[kind])
+		}
+	}
+
+	// Email delivery
+	writeMetricHeader(&b, "nestanak_emails_sent_total", "counter", "Emails accepted by the Brevo API by type.")
+	for _, emailType := range sortedKeys(m.metrics.emailsSent) {
+		fmt.Fprintf(&b, "nestanak_emails_sent_total{type=\"%s\"} %d\n", escapeLabelValue(emailType), m.metrics.emailsSent[emailType])
+	}
+
+	writeMetricHeader(&b, "nestanak_emails_failed_total", "counter", "Emails that failed to send by type.")
+	for _, emailType := range sortedKeys(m.metrics.emailsFailed) {
+		fmt.Fprintf(&b, "nestanak_emails_failed_total{type=\"%s\"} %d\n", escapeLabelValue(emailType), m.metrics.emailsFailed[emailType])
+	}
+	m.metrics.mu.Unlock()
+
+	// DNS cache
+	hits, misses, ipChanges := m.dnsCache.GetCounters()
+	writeMetricHeader(&b, "nestanak_dns_cache_hits_total", "counter", "DNS resolutions served from cache.")
+	fmt.Fprintf(&b, "nestanak_dns_cache_hits_total %d\n", hits)
+	writeMetricHeader(&b, "nestanak_dns_cache_misses_total", "counter", "DNS resolutions that required a lookup.")
+	fmt.Fprintf(&b, "nestanak_dns_cache_misses_total %d\n", misses)
+	writeMetricHeader(&b, "nestanak_dns_ip_changes_total", "counter", "Times a cached hostname resolved to a different IP.")
+	fmt.Fprintf(&b, "nestanak_dns_ip_changes_total %d\n", ipChanges)
+	writeMetricHeader(&b, "nestanak_dns_cache_entries", "gauge", "Hostnames currently in the DNS cache.")
+	fmt.Fprintf(&b, "nestanak_dns_cache_entries %d\n", m.dnsCache.EntryCount())
+
+	// HTTP server
+	writeMetricHeader(&b, "nestanak_http_rate_limited_total", "counter", "Web requests rejected by the per-IP rate limiter.")
+	fmt.Fprintf(&b, "nestanak_http_rate_limited_total %d\n", m.httpRateLimiter.Rejected())
+
+	writeMetricHeader(&b, "nestanak_uptime_seconds", "gauge", "Seconds since the service started.")
+	fmt.Fprintf(&b, "nestanak_uptime_seconds %d\n", int(time.Since(m.statsStartTime).Seconds()))
+
+	return b.String()
+}
+
+// boolToInt converts a bool to 0/1 for gauge values
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// metricsAuthMiddleware requires the configured bearer token, if any
+// Without a token, metrics served on the main listener (shared) require the web interface login like
+// every other page there; a dedicated metrics_listen address is left to the operator to restrict
+func (m *Monitor) metricsAuthMiddleware(next http.HandlerFunc, shared bool) http.HandlerFunc {
+	loginRequired := m.AuthMiddleware(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m.getConfig().MetricsBearerToken != "" {
+			expected := "Bearer " + m.getConfig().MetricsBearerToken
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(expected)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+			return
+		}
+		if shared {
+			loginRequired(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleMetrics serves metrics in the Prometheus text format
+func (m *Monitor) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprint(w, m.renderMetrics())
+}
+
+// startMetricsServer registers /metrics on the main HTTP server, or starts a
+// dedicated listener when metrics_listen is set
+func (m *Monitor) startMetricsServer() {
+	if !m.getConfig().MetricsEnabled {
+		return
+	}
+
+	if m.getConfig().MetricsListen == "" {
+		http.HandleFunc("/metrics", securityHeadersMiddleware(m.rateLimitMiddleware(m.metricsAuthMiddleware(m.handleMetrics, true))))
+		log.Printf("📈 Metrics available at /metrics on %s", m.getConfig().HTTPListen)
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", securityHeadersMiddleware(m.metricsAuthMiddleware(m.handleMetrics, false)))
+
+	go func() {
+		log.Printf("📈 Starting metrics server on %s", m.getConfig().MetricsListen)
+		m.addLog(fmt.Sprintf("Starting metrics server on %s", m.getConfig().MetricsListen))
+
+		// A busy port only loses the metrics, the monitor keeps running
+		if err := http.ListenAndServe(m.getConfig().MetricsListen, mux); err != nil {
+			log.Printf("❌ Metrics server on %s stopped: %v", m.getConfig().MetricsListen, err)
+			m.addLog(fmt.Sprintf("Metrics server on %s stopped: %v", m.getConfig().MetricsListen, err))
+		}
+	}()
+}
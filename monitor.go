@@ -1,15 +1,18 @@
 package main
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"html/template"
-	"io"
 	"log"
 	"net/http"
-	"net/url"
+	"net/http/cookiejar"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/html"
@@ -18,10 +21,11 @@ import (
 // Monitor manages the URL checking service
 type Monitor struct {
 	userAgentManager         *UserAgentManager
-	config                   Config
+	config                   atomic.Pointer[Config]  // Running configuration, replaced as a whole on reload (see getConfig)
 	state                    *ServiceState           // Persistent state across restarts
 	lastAlertTime            map[AlertKey]time.Time
 	emailsSentThisHour       []time.Time
+	subscriptionEmailsThisHour []time.Time           // Confirmation emails sent in the last hour (own budget, see allowSubscriptionEmail)
 	emailsSentPerURLToday    map[string][]time.Time // Track emails per URL per day (in-memory, synced with state)
 	errorEmailsSentPerURLToday map[string][]time.Time // Track error emails per URL per day (in-memory, synced with state)
 	foundURLs                map[string]bool
@@ -31,19 +35,47 @@ type Monitor struct {
 	asyncLogger              *AsyncLogger
 	workerPool               *WorkerPool
 	dnsCache                 *DNSCache               // DNS resolution cache
+	httpTransport            *http.Transport         // Shared by all URL checks (keep-alive, dials through dnsCache)
 	httpRateLimiter          *HTTPRateLimiter
+	metrics                  *Metrics                // Prometheus counters
+	events                   *EventBroker            // Live dashboard updates (nil if HTTP is disabled)
 	sessionManager           *SessionManager
 	templates                *template.Template
 	statsStartTime           time.Time
 	lastCheckTime            time.Time               // When the last check cycle completed (legacy, for compatibility)
 	perURLCheckTime          map[string]time.Time    // Last check time per URL
+	perURLSuccessTime        map[string]time.Time    // Last check that got the real page per URL (for readiness)
+	nextCheckTime            map[string]time.Time    // When each URL's check loop runs next (from its interval or schedule)
+	checksInFlight           map[string]bool         // URLs with a check currently running
+	forgetPending            map[string]bool         // Removed URLs whose state is dropped once their running check ends (see endCheck)
+	failureStreaks           map[string]FailureStreak // Consecutive failed checks per URL
+	stateChanges             map[string][]time.Time  // Recent reachability changes per URL (flapping detection)
+	flappingURLs             map[string]bool         // URLs changing state too often to send error/recovery emails
+	errorNotified            map[string]bool         // URLs whose current outage was reported by email
+	softErrors               map[string]string       // Soft error kind of the last page received per URL (maintenance, portal or empty page)
+	throttledUntil           map[string]time.Time    // URLs whose site asked to wait (429, or 503 with Retry-After), until when
+	checkHistory             map[string][]CheckRecord // Recent checks per URL, oldest first
+	snapshots                map[string][]*URLSnapshot // Recent distinct page versions per URL, oldest first
+	pageCache                map[string]*PageCacheEntry // Validators and parsed result of the last page per URL
+	urlTransports            map[string]urlTransport // Transports of URLs with their own TLS settings
+	cookieJars               map[string]*cookiejar.Jar // Cookie jars of URLs with cookie_jar enabled
+	lastStateSave            time.Time               // Last successful SaveState
+	lastStateSaveError       string                  // Error from the last SaveState attempt, empty if it succeeded
+	lastEmailSuccess         time.Time               // Last email accepted by Brevo
+	lastEmailFailure         time.Time               // Last failed email delivery
+	lastEmailError           string                  // Error from the last failed delivery
 	stopChan                 chan struct{}           // Signal to stop all goroutines
+	urlStops                 map[string]chan struct{} // Per-URL stop signal for monitorURL goroutines
+	urlWG                    sync.WaitGroup          // Running monitorURL goroutines
+	configPath               string                  // Config file that URL edits are written back to
+	configMu                 sync.Mutex              // Serializes URL edits from the web UI and config reloads
+	configHash               [32]byte                // SHA-256 of the config file as last loaded or written (guarded by configMu)
 	mu                       sync.RWMutex
 	emailMu                  sync.Mutex
 }
 
 // NewMonitor creates a new monitor instance
-func NewMonitor(config Config) *Monitor {
+func NewMonitor(config Config, configPath string) *Monitor {
 	// Load persistent state
 	state := LoadState(config.StateFilePath)
 	
@@ -53,21 +85,9 @@ func NewMonitor(config Config) *Monitor {
 
 	// Create User-Agent manager
 	userAgentManager := NewUserAgentManager()
-	
-	// Fetch recent User-Agents if rotation is enabled (non-blocking, falls back on failure)
-	if config.UserAgentRotation {
-		go func() {
-			if err := userAgentManager.FetchUserAgents(config); err != nil {
-				log.Printf("⚠️  Using fallback User-Agent due to fetch failure")
-			}
-		}()
-	} else {
-		log.Printf("ℹ️  User-Agent rotation disabled, using static User-Agent")
-	}
 
 	m := &Monitor{
 		userAgentManager:         userAgentManager,
-		config:                     config,
 		state:                      state,
 		lastAlertTime:              make(map[AlertKey]time.Time),
 		emailsSentThisHour:         make([]time.Time, 0),
@@ -79,10 +99,38 @@ func NewMonitor(config Config) *Monitor {
 		recentEvents:               NewCircularBuffer(config.RecentEventsBufferSize),
 		workerPool:                 NewWorkerPool(config.MaxConcurrentChecks),
 		dnsCache:                   dnsCache,
+		metrics:                    NewMetrics(),
 		statsStartTime:             time.Now(),
 		perURLCheckTime:            make(map[string]time.Time),
+		perURLSuccessTime:          make(map[string]time.Time),
+		nextCheckTime:              make(map[string]time.Time),
+		checksInFlight:             make(map[string]bool),
+		forgetPending:              make(map[string]bool),
+		failureStreaks:             make(map[string]FailureStreak),
+		stateChanges:               make(map[string][]time.Time),
+		flappingURLs:               make(map[string]bool),
+		errorNotified:              make(map[string]bool),
+		softErrors:                 make(map[string]string),
+		throttledUntil:             make(map[string]time.Time),
+		checkHistory:               make(map[string][]CheckRecord),
+		snapshots:                  make(map[string][]*URLSnapshot),
+		pageCache:                  make(map[string]*PageCacheEntry),
+		urlTransports:              make(map[string]urlTransport),
+		cookieJars:                 make(map[string]*cookiejar.Jar),
+		urlStops:                   make(map[string]chan struct{}),
+		configPath:                 configPath,
 		stopChan:                   make(chan struct{}),
 	}
+	m.config.Store(&config)
+	m.httpTransport = m.newCheckTransport()
+
+	// Keep the User-Agent pool current if rotation is enabled (non-blocking, starts from the cached list)
+	if config.UserAgentRotation {
+		go m.refreshUserAgents()
+	} else {
+		userAgentManager.setRotationDisabled()
+		log.Printf("ℹ️  User-Agent rotation disabled, using static User-Agent")
+	}
 
 	// Initialize async logger
 	m.asyncLogger = NewAsyncLogger(
@@ -90,13 +138,14 @@ func NewMonitor(config Config) *Monitor {
 		time.Duration(config.LogBufferFlushSeconds)*time.Second,
 	)
 
-	// Initialize HTTP rate limiter if HTTP is enabled
+	// Initialize HTTP rate limiter and live event broker if HTTP is enabled
 	if config.HTTPEnabled {
 		m.httpRateLimiter = &HTTPRateLimiter{
 			requests: make(map[string][]time.Time),
 			limit:    config.HTTPRateLimitPerMinute,
 			window:   time.Minute,
 		}
+		m.events = NewEventBroker(maxSSEClients)
 	}
 
 	// Initialize session manager if auth is enabled
@@ -110,11 +159,11 @@ func NewMonitor(config Config) *Monitor {
 // Start starts the monitoring service with independent goroutines per URL
 func (m *Monitor) Start() {
 	m.addLog("🎯 Nestanak-Info Service Started")
-	log.Printf("🔍 Monitoring %d URLs with independent check goroutines", len(m.config.URLConfigs))
-	log.Printf("📧 Sending alerts to %d recipients", len(m.config.Recipients))
-	log.Printf("🚫 Email limit: %d per URL per day", m.config.MaxEmailsPerURLPerDay)
-	log.Printf("🌐 DNS cache TTL: %d minutes", m.config.DNSCacheTTLMinutes)
-	log.Printf("⏱️  Check interval: %d seconds per URL", m.config.CheckIntervalSeconds)
+	log.Printf("🔍 Monitoring %d URLs with independent check goroutines", len(m.getConfig().URLConfigs))
+	log.Printf("📧 Sending alerts to %d recipients", len(m.getConfig().Recipients))
+	log.Printf("🚫 Email limit: %d per URL per day", m.getConfig().MaxEmailsPerURLPerDay)
+	log.Printf("🌐 DNS cache TTL: %d minutes", m.getConfig().DNSCacheTTLMinutes)
+	log.Printf("⏱️  Check interval: %d seconds per URL", m.getConfig().CheckIntervalSeconds)
 	
 	// Log state statistics
 	if m.state != nil {
@@ -124,20 +173,29 @@ func (m *Monitor) Start() {
 	}
 
 	// Initialize templates if HTTP is enabled
-	if m.config.HTTPEnabled {
+	if m.getConfig().HTTPEnabled {
 		m.templates = initTemplates()
 		m.startHTTPServer()
 	}
 
+	// Metrics are served on the main HTTP server or a dedicated listener
+	m.startMetricsServer()
+
 	// Start independent goroutine for each URL with staggered timing
-	var wg sync.WaitGroup
-	for i, urlConfig := range m.config.URLConfigs {
-		wg.Add(1)
-		go m.monitorURL(urlConfig, i, &wg)
+	// Spread URLs evenly across the check interval
+	urlConfigs := m.getURLConfigs()
+	intervalDuration := time.Duration(m.getConfig().CheckIntervalSeconds) * time.Second
+	for i, urlConfig := range urlConfigs {
+		m.startURLMonitor(urlConfig, (intervalDuration/time.Duration(len(urlConfigs)))*time.Duration(i))
+	}
+
+	// Reload the config file when it changes (SIGHUP always reloads, see main)
+	if m.getConfig().ConfigWatchIntervalSeconds > 0 {
+		go m.watchConfigFile(time.Duration(m.getConfig().ConfigWatchIntervalSeconds) * time.Second)
 	}
 
 	// Start state persistence ticker (every 5 minutes)
-	stateTicker := time.NewTicker(5 * time.Minute)
+	stateTicker := time.NewTicker(stateSaveInterval)
 	defer stateTicker.Stop()
 
 	// Start DNS cache cleanup ticker (every 10 minutes)
@@ -158,8 +216,9 @@ func (m *Monitor) Start() {
 		}
 	}()
 
-	// Wait for all URL monitors to finish (they run forever until stopChan is closed)
-	wg.Wait()
+	// Wait for all URL monitors to finish (they run until stopChan is closed or the URL is removed)
+	<-m.stopChan
+	m.urlWG.Wait()
 }
 
 // Shutdown gracefully shuts down the monitor
@@ -185,13 +244,16 @@ func (m *Monitor) Shutdown() {
 	if m.workerPool != nil {
 		m.workerPool.Stop()
 	}
+
+	// Close kept-alive connections to the monitored servers
+	m.closeIdleConnections()
 	
 	log.Printf("✅ Monitor shutdown complete")
 }
 
 // saveState persists current state to disk
 func (m *Monitor) saveState() {
-	if m.state == nil || m.config.StateFilePath == "" {
+	if m.state == nil || m.getConfig().StateFilePath == "" {
 		return
 	}
 
@@ -202,21 +264,30 @@ func (m *Monitor) saveState() {
 	m.emailMu.Unlock()
 
 	// Save to file
-	if err := m.state.SaveState(m.config.StateFilePath); err != nil {
+	err := m.state.SaveState(m.getConfig().StateFilePath)
+
+	m.mu.Lock()
+	if err != nil {
+		m.lastStateSaveError = err.Error()
+	} else {
+		m.lastStateSave = time.Now()
+		m.lastStateSaveError = ""
+	}
+	m.mu.Unlock()
+
+	if err != nil {
 		log.Printf("⚠️  Failed to save state: %v", err)
 	} else {
-		log.Printf("💾 State saved to %s", m.config.StateFilePath)
+		log.Printf("💾 State saved to %s", m.getConfig().StateFilePath)
 	}
 }
 
-// monitorURL runs an independent check loop for a single URL
-func (m *Monitor) monitorURL(urlConfig URLConfig, index int, wg *sync.WaitGroup) {
-	defer wg.Done()
+// monitorURL runs an independent check loop for a single URL until stopChan or stop is closed
+// Checks follow the URL's schedule if it has one, otherwise its check interval
+func (m *Monitor) monitorURL(urlConfig URLConfig, staggerDelay time.Duration, stop chan struct{}) {
+	defer m.urlWG.Done()
 
-	// Calculate staggered start delay to distribute checks
-	// Spread URLs evenly across the check interval
-	intervalDuration := time.Duration(m.config.CheckIntervalSeconds) * time.Second
-	staggerDelay := (intervalDuration / time.Duration(len(m.config.URLConfigs))) * time.Duration(index)
+	settings := m.getConfig().urlSettings(urlConfig)
 	
 	displayName := urlConfig.Name
 	if displayName == "" {
@@ -225,42 +296,230 @@ func (m *Monitor) monitorURL(urlConfig URLConfig, index int, wg *sync.WaitGroup)
 	
 	log.Printf("🔄 URL monitor starting for '%s' (stagger: %v)", displayName, staggerDelay)
 	
-	// Initial staggered delay
-	select {
-	case <-time.After(staggerDelay):
-	case <-m.stopChan:
-		return
-	}
+	// Adaptive polling replaces the fixed interval (URLs with a schedule keep it)
+	adaptive := m.getConfig().AdaptivePolling && settings.schedule == nil
+	poll := &pollState{interval: time.Duration(settings.CheckIntervalSeconds) * time.Second}
 
-	// Run first check immediately after stagger
-	m.checkSingleURL(urlConfig)
+	// Initial staggered delay, then the first check runs right away
+	next := time.Now().Add(staggerDelay)
+	for {
+		m.setNextCheckTime(urlConfig.URL, next)
 
-	// Set up ticker for periodic checks
-	ticker := time.NewTicker(intervalDuration)
-	defer ticker.Stop()
+		var due <-chan time.Time // nil (never fires) if the schedule has no further runs
+		if !next.IsZero() {
+			due = time.After(time.Until(next))
+		}
 
-	// Check loop
-	for {
 		select {
-		case <-ticker.C:
-			m.checkSingleURL(urlConfig)
+		case <-due:
 		case <-m.stopChan:
 			log.Printf("🛑 Stopping monitor for '%s'", displayName)
 			return
+		case <-stop:
+			log.Printf("🛑 Stopping monitor for '%s' (removed or changed)", displayName)
+			return
 		}
+
+		// A manual check may have been asked to wait since this check was scheduled
+		if until := m.deferForThrottle(urlConfig.URL, time.Now()); time.Until(until) > 0 {
+			next = until
+			continue
+		}
+
+		// Schedule from the start of this check so check duration does not cause drift
+		start := time.Now()
+		next = m.nextScheduledCheck(settings, start)
+		m.setNextCheckTime(urlConfig.URL, next)
+		result, checked := m.checkSingleURL(urlConfig, stop)
+		if adaptive && checked {
+			poll.observe(result)
+			next = start.Add(m.adaptiveInterval(urlConfig.URL, settings, poll, start))
+		}
+		next = m.deferForThrottle(urlConfig.URL, next)
+	}
+}
+
+// nextScheduledCheck returns when a URL is due after a check that started at last
+// Schedules are evaluated in local time (time_offset_hours); zero means never
+func (m *Monitor) nextScheduledCheck(settings URLSettings, last time.Time) time.Time {
+	if settings.schedule == nil {
+		return last.Add(time.Duration(settings.CheckIntervalSeconds) * time.Second)
+	}
+	offset := time.Duration(m.getConfig().TimeOffsetHours) * time.Hour
+	next := settings.schedule.next(last.Add(offset))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(-offset)
+}
+
+// setNextCheckTime records when a URL's check loop runs next
+func (m *Monitor) setNextCheckTime(url string, next time.Time) {
+	m.mu.Lock()
+	m.nextCheckTime[url] = next
+	m.mu.Unlock()
+}
+
+// startURLMonitor starts the check loop for a URL
+func (m *Monitor) startURLMonitor(urlConfig URLConfig, staggerDelay time.Duration) {
+	stop := make(chan struct{})
+
+	m.mu.Lock()
+	m.urlStops[urlConfig.URL] = stop
+	m.mu.Unlock()
+
+	m.urlWG.Add(1)
+	go m.monitorURL(urlConfig, staggerDelay, stop)
+}
+
+// stopURLMonitor stops the check loop for a URL
+// A check already running finishes first, but its result is dropped (see runCheck)
+func (m *Monitor) stopURLMonitor(url string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if stop, exists := m.urlStops[url]; exists {
+		close(stop)
+		delete(m.urlStops, url)
 	}
 }
 
+// forgetURL drops the in-memory status of a URL that is no longer monitored
+// While a check of the URL is still running this is deferred until it ends (see endCheck),
+// so the check cannot write its state back afterwards
+func (m *Monitor) forgetURL(url string) {
+	m.mu.Lock()
+	if m.checksInFlight[url] {
+		m.forgetPending[url] = true
+		m.mu.Unlock()
+		return
+	}
+	delete(m.foundURLs, url)
+	delete(m.unreachableURLs, url)
+	delete(m.lastURLDownTime, url)
+	delete(m.perURLCheckTime, url)
+	delete(m.perURLSuccessTime, url)
+	delete(m.nextCheckTime, url)
+	delete(m.checkHistory, url)
+	delete(m.snapshots, url)
+	delete(m.pageCache, url)
+	if cached, ok := m.urlTransports[url]; ok {
+		cached.transport.CloseIdleConnections()
+		delete(m.urlTransports, url)
+	}
+	delete(m.cookieJars, url)
+	delete(m.failureStreaks, url)
+	delete(m.stateChanges, url)
+	delete(m.flappingURLs, url)
+	delete(m.errorNotified, url)
+	delete(m.softErrors, url)
+	delete(m.throttledUntil, url)
+	m.mu.Unlock()
+
+	m.metrics.mu.Lock()
+	delete(m.metrics.checks, url)
+	m.metrics.mu.Unlock()
+}
+
 // checkSingleURL checks a single URL and handles the result
-func (m *Monitor) checkSingleURL(urlConfig URLConfig) {
-	result := m.checkURL(urlConfig)
+// It returns false if the check was skipped because another one was running, or its loop was stopped meanwhile
+// It is skipped if a check of the same URL is already running (e.g. a manual "check now")
+func (m *Monitor) checkSingleURL(urlConfig URLConfig, stop <-chan struct{}) (URLCheckResult, bool) {
+	if !m.beginCheck(urlConfig.URL) {
+		log.Printf("⏭️  Skipping scheduled check of %s - a check is already running", urlConfig.URL)
+		return URLCheckResult{}, false
+	}
+	defer m.endCheck(urlConfig.URL)
+
+	return m.runCheck(urlConfig, false, stop)
+}
+
+// beginCheck marks a URL as being checked, returning false if a check is already running
+func (m *Monitor) beginCheck(url string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.checksInFlight[url] {
+		return false
+	}
+	m.checksInFlight[url] = true
+	return true
+}
+
+// endCheck clears the in-flight mark set by beginCheck, forgetting the URL if it was removed meanwhile
+func (m *Monitor) endCheck(url string) {
+	m.mu.Lock()
+	delete(m.checksInFlight, url)
+	forget := m.forgetPending[url]
+	delete(m.forgetPending, url)
+	m.mu.Unlock()
+
+	if forget {
+		m.forgetURL(url)
+	}
+}
+
+// Errors returned by triggerCheck
+var (
+	errCheckInProgress = errors.New("check already in progress")
+	errWorkerPoolBusy  = errors.New("worker pool is busy")
+)
+
+// triggerCheck schedules an out-of-band check on the worker pool
+// Returns errCheckInProgress if the URL is already being checked, errWorkerPoolBusy if the pool is full,
+// a checkPausedError while the site asked to wait (a manual check would only reset the pause)
+func (m *Monitor) triggerCheck(urlConfig URLConfig) error {
+	if until := m.pausedUntil(urlConfig.URL); !until.IsZero() {
+		return checkPausedError{until: until}
+	}
+	if !m.beginCheck(urlConfig.URL) {
+		return errCheckInProgress
+	}
+
+	// The result is dropped if the URL is removed or changed while the check runs
+	m.mu.RLock()
+	stop := m.urlStops[urlConfig.URL]
+	m.mu.RUnlock()
+
+	submitted := m.workerPool.Submit(func() {
+		defer m.endCheck(urlConfig.URL)
+		m.runCheck(urlConfig, true, stop)
+	})
+	if !submitted {
+		m.endCheck(urlConfig.URL)
+		return errWorkerPoolBusy
+	}
+
+	log.Printf("🔄 Manual check scheduled for %s", urlConfig.URL)
+	m.addLog(fmt.Sprintf("Manual check scheduled for %s", urlConfig.URL))
+	return nil
+}
+
+// runCheck performs a check and records its outcome (callers hold the in-flight mark)
+// If stop (the URL's check loop) or the service was stopped meanwhile, the result is dropped and false returned:
+// the URL was removed or changed and its state must not be brought back
+func (m *Monitor) runCheck(urlConfig URLConfig, manual bool, stop <-chan struct{}) (URLCheckResult, bool) {
+	result := m.checkURLWithRetries(urlConfig, stop)
+	if isStopped(stop) || isStopped(m.stopChan) {
+		log.Printf("🗑️  Dropping result of the check of %s - its monitor was stopped", urlConfig.URL)
+		return result, false
+	}
+	result.Manual = manual
+	m.metrics.ObserveCheck(result)
 	m.handleCheckResult(result)
 	
 	// Update per-URL check time
 	m.mu.Lock()
 	m.perURLCheckTime[urlConfig.URL] = time.Now()
 	m.lastCheckTime = time.Now() // Also update legacy field for compatibility
+	if result.Error == nil && result.SoftError == "" {
+		m.perURLSuccessTime[urlConfig.URL] = time.Now()
+	}
 	m.mu.Unlock()
+
+	m.recordCheckHistory(result)
+	m.publishCheck(result)
+	return result, true
 }
 
 // checkURL checks a single URL for search terms
@@ -269,38 +528,40 @@ func (m *Monitor) checkURL(urlConfig URLConfig) URLCheckResult {
 		URL:         urlConfig.URL,
 		Name:        urlConfig.Name,
 		SearchTerms: urlConfig.SearchTerms,
+		Extractor:   urlConfig.extractorType(),
 		CheckedAt:   time.Now(),
 	}
 
-	// Parse URL to extract hostname for DNS caching
-	parsedURL, err := url.Parse(urlConfig.URL)
-	if err == nil && parsedURL.Hostname() != "" {
-		// Resolve DNS with caching
-		_, ipChanged, dnsErr := m.dnsCache.Resolve(parsedURL.Hostname())
-		if dnsErr != nil {
-			log.Printf("⚠️  DNS resolution warning for %s: %v (will try HTTP anyway)", parsedURL.Hostname(), dnsErr)
-		}
-		if ipChanged {
-			displayName := urlConfig.Name
-			if displayName == "" {
-				displayName = urlConfig.URL
-			}
-			m.addLog(fmt.Sprintf("🔄 DNS IP changed for %s", displayName))
-		}
+	// The shared transport keeps connections alive and dials through the DNS cache
+	// (URLs with TLS options get a copy with their own TLS settings)
+	transport, err := m.checkTransport(urlConfig)
+	if err != nil {
+		result.Error = err
+		return result
 	}
-
+	settings := m.getConfig().urlSettings(urlConfig)
 	client := &http.Client{
-		Timeout: time.Duration(m.config.ConnectTimeout) * time.Second,
+		Transport: m.roundTripper(transport, settings.Proxy),
+		Jar:       m.cookieJar(urlConfig),
+		Timeout:   time.Duration(settings.ConnectTimeout) * time.Second,
 	}
 
-	// Create request with rotating User-Agent header
+	// Create request with a rotating User-Agent and the headers its browser sends, then the URL's own headers
 	req, err := http.NewRequest("GET", urlConfig.URL, nil)
 	if err != nil {
 		result.Error = err
 		return result
 	}
-	currentUserAgent := m.userAgentManager.GetNext()
-	req.Header.Set("User-Agent", currentUserAgent)
+	userAgent := customUserAgent(urlConfig.Headers)
+	if userAgent == "" {
+		userAgent = m.userAgentManager.GetNext()
+	}
+	setBrowserHeaders(req, userAgent)
+	applyRequestHeaders(req, urlConfig.Headers)
+
+	// Ask for the page only if it changed since the last fetch
+	cached := m.cachedPage(urlConfig)
+	setConditionalHeaders(req, cached)
 
 	startTime := time.Now()
 	resp, err := client.Do(req)
@@ -312,20 +573,47 @@ func (m *Monitor) checkURL(urlConfig URLConfig) URLCheckResult {
 		return result
 	}
 	defer resp.Body.Close()
+	result.StatusCode = resp.StatusCode
+
+	// Not modified: reuse the previous result without downloading or parsing the page
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return reuseCachedResult(cached, result)
+	}
+
+	// Too many requests, or unavailable with Retry-After: the site asks to come back later
+	if delay, throttled := m.throttleDelay(resp, result.CheckedAt); throttled {
+		result.Throttled = true
+		result.RetryAfter = delay
+		result.Error = fmt.Errorf("HTTP %d, retry after %s", resp.StatusCode, delay)
+		return result
+	}
 
 	if resp.StatusCode != http.StatusOK {
 		result.Error = fmt.Errorf("HTTP %d", resp.StatusCode)
 		return result
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Only read pages of an expected type and size (not a download, an API error or endless garbage)
+	if err := checkContentType(resp.Header, m.getConfig().AllowedContentTypes); err != nil {
+		result.Error = err
+		return result
+	}
+	body, err := readBody(resp, int64(m.getConfig().MaxBodyKB)*1024)
 	if err != nil {
 		result.Error = err
 		return result
 	}
 
+	// Same body as last time (server without ETag/Last-Modified): skip parsing, refresh the validators
+	bodyHash := sha256.Sum256(body)
+	if cached != nil && cached.BodyHash == bodyHash {
+		result = reuseCachedResult(cached, result)
+		m.storePage(urlConfig, resp.Header, bodyHash, result)
+		return result
+	}
+
 	bodyStr := string(body)
+	result.Body = bodyStr
 
 	// Check if content contains all search terms for this URL
 	if containsAllSearchTerms(bodyStr, urlConfig.SearchTerms) {
@@ -333,7 +621,7 @@ func (m *Monitor) checkURL(urlConfig URLConfig) URLCheckResult {
 		result.FoundTerms = urlConfig.SearchTerms
 		
 		// Extract detailed information based on URL type
-		if strings.Contains(urlConfig.URL, "bvk.rs") {
+		if result.Extractor == extractorWater {
 			// Water outage extraction (different format)
 			result.Date = extractDateWater(bodyStr, urlConfig.SearchTerms)
 			result.Time = extractTimeWater(bodyStr, urlConfig.URL)
@@ -346,11 +634,28 @@ func (m *Monitor) checkURL(urlConfig URLConfig) URLCheckResult {
 		}
 	}
 
+	// A page without the terms may not be the real page; soft error pages are not cached,
+	// so the next check fetches and inspects the page again
+	if !result.Found {
+		result.SoftError, result.SoftDetail = detectSoftError(bodyStr, req.URL, resp.Request.URL, m.getConfig().SoftErrorMarkers)
+		if result.SoftError != "" {
+			return result
+		}
+	}
+
+	m.storePage(urlConfig, resp.Header, bodyHash, result)
 	return result
 }
 
 // handleCheckResult handles the result of a URL check
 func (m *Monitor) handleCheckResult(result URLCheckResult) {
+	// Polite throttling pauses the URL's checks but is not counted as a failure
+	if result.Throttled {
+		m.handleThrottled(result)
+		return
+	}
+	m.clearThrottle(result.URL)
+
 	if result.Error != nil {
 		log.Printf("⚠️  Error checking %s: %v", result.URL, result.Error)
 		m.addLog(fmt.Sprintf("Error checking %s: %v", result.URL, result.Error))
@@ -363,6 +668,27 @@ func (m *Monitor) handleCheckResult(result URLCheckResult) {
 	// URL is reachable - check if it was previously down
 	m.handleConnectionRecovery(result)
 
+	// Not the real page: keep the previous found/not found state instead of reporting "not found"
+	m.mu.Lock()
+	previousSoftError := m.softErrors[result.URL]
+	if result.SoftError != "" {
+		m.softErrors[result.URL] = result.SoftError
+	} else {
+		delete(m.softErrors, result.URL)
+	}
+	m.mu.Unlock()
+	if result.SoftError != "" {
+		log.Printf("🚧 %s returned a %s page (%s), keeping the previous result", result.URL, result.SoftError, result.SoftDetail)
+		if previousSoftError != result.SoftError {
+			m.addLog(fmt.Sprintf("%s returned a %s page (%s)", result.URL, result.SoftError, result.SoftDetail))
+		}
+		return
+	}
+	if previousSoftError != "" {
+		log.Printf("✅ %s serves the real page again (was %s)", result.URL, previousSoftError)
+		m.addLog(fmt.Sprintf("%s serves the real page again", result.URL))
+	}
+
 	m.mu.Lock()
 	wasFound := m.foundURLs[result.URL]
 	m.foundURLs[result.URL] = result.Found
@@ -390,7 +716,8 @@ func (m *Monitor) handleCheckResult(result URLCheckResult) {
 				SearchTerms: result.FoundTerms,
 				Message:     fmt.Sprintf("Search terms found: %s", strings.Join(result.FoundTerms, ", ")),
 			}
-			m.recentEvents.Add(event)
+			m.recordEvent(event)
+			m.publishState(result, "found")
 
 			// Send alert if allowed and not already seen
 			if alreadySeen {
@@ -433,7 +760,8 @@ func (m *Monitor) handleCheckResult(result URLCheckResult) {
 			URL:       result.URL,
 			Message:   "Search terms no longer found",
 		}
-		m.recentEvents.Add(event)
+		m.recordEvent(event)
+		m.publishState(result, "not_found")
 	} else {
 		log.Printf("✓ No terms found on %s", result.URL)
 	}
@@ -446,9 +774,11 @@ func (m *Monitor) canSendAlert(url string, alertType string) bool {
 	lastAlert, exists := m.lastAlertTime[key]
 	m.mu.RUnlock()
 
+	settings := m.settingsForURL(url)
+
 	// Check cooldown
 	if exists {
-		cooldownDuration := time.Duration(m.config.AlertCooldownMinutes) * time.Minute
+		cooldownDuration := time.Duration(settings.AlertCooldownMinutes) * time.Minute
 		if time.Since(lastAlert) < cooldownDuration {
 			log.Printf("⏱️  Alert cooldown active for %s (%s)", url, alertType)
 			return false
@@ -469,8 +799,8 @@ func (m *Monitor) canSendAlert(url string, alertType string) bool {
 	}
 	m.emailsSentThisHour = validEmails
 
-	if len(m.emailsSentThisHour) >= m.config.EmailRateLimitPerHour {
-		log.Printf("⚠️  Global email rate limit reached (%d/hour)", m.config.EmailRateLimitPerHour)
+	if len(m.emailsSentThisHour) >= m.getConfig().EmailRateLimitPerHour {
+		log.Printf("⚠️  Global email rate limit reached (%d/hour)", m.getConfig().EmailRateLimitPerHour)
 		return false
 	}
 
@@ -486,8 +816,8 @@ func (m *Monitor) canSendAlert(url string, alertType string) bool {
 		}
 		m.emailsSentPerURLToday[url] = validURLEmails
 
-		if len(validURLEmails) >= m.config.MaxEmailsPerURLPerDay {
-			log.Printf("⚠️  Daily email limit reached for URL %s (%d/%d)", url, len(validURLEmails), m.config.MaxEmailsPerURLPerDay)
+		if len(validURLEmails) >= settings.MaxEmailsPerDay {
+			log.Printf("⚠️  Daily email limit reached for URL %s (%d/%d)", url, len(validURLEmails), settings.MaxEmailsPerDay)
 			return false
 		}
 	}
@@ -495,6 +825,23 @@ func (m *Monitor) canSendAlert(url string, alertType string) bool {
 	return true
 }
 
+// reserveAlertEmail takes one delivery from the global hourly email budget
+// It returns false when email_rate_limit_per_hour has been used up
+func (m *Monitor) reserveAlertEmail() bool {
+	m.emailMu.Lock()
+	defer m.emailMu.Unlock()
+
+	oneHourAgo := time.Now().Add(-time.Hour)
+	m.emailsSentThisHour = slices.DeleteFunc(m.emailsSentThisHour, func(t time.Time) bool {
+		return !t.After(oneHourAgo)
+	})
+	if len(m.emailsSentThisHour) >= m.getConfig().EmailRateLimitPerHour {
+		return false
+	}
+	m.emailsSentThisHour = append(m.emailsSentThisHour, time.Now())
+	return true
+}
+
 // recordAlert records that an alert was sent
 func (m *Monitor) recordAlert(url string, alertType string) {
 	now := time.Now()
@@ -504,9 +851,8 @@ func (m *Monitor) recordAlert(url string, alertType string) {
 	m.lastAlertTime[key] = now
 	m.mu.Unlock()
 
+	// The global hourly budget is charged per delivery (see reserveAlertEmail)
 	m.emailMu.Lock()
-	m.emailsSentThisHour = append(m.emailsSentThisHour, now)
-	
 	// Track per-URL emails
 	if m.emailsSentPerURLToday[url] == nil {
 		m.emailsSentPerURLToday[url] = make([]time.Time, 0)
@@ -533,18 +879,79 @@ func (m *Monitor) getRecentLogs() []LogEntry {
 	return []LogEntry{}
 }
 
-// getRecentMatches returns recent matches for display
-func (m *Monitor) getRecentMatches() []IncidentInfo {
-	matches := make([]IncidentInfo, 0)
+// getConfig returns the running configuration
+// The returned Config is shared and must not be modified; reloads replace it as a whole
+func (m *Monitor) getConfig() *Config {
+	return m.config.Load()
+}
+
+// getURLConfigs returns a copy of the monitored URLs (they can change at runtime)
+func (m *Monitor) getURLConfigs() []URLConfig {
+	return slices.Clone(m.getConfig().URLConfigs)
+}
+
+// findURLConfig returns the monitored URL with the given ID (see urlID)
+func (m *Monitor) findURLConfig(id string) (URLConfig, bool) {
+	for _, urlConfig := range m.getURLConfigs() {
+		if urlID(urlConfig.URL) == id {
+			return urlConfig, true
+		}
+	}
+	return URLConfig{}, false
+}
+
+// settingsForURL returns the settings in effect for a monitored URL (global values if it is not monitored)
+func (m *Monitor) settingsForURL(url string) URLSettings {
+	config := m.getConfig()
+	for _, urlConfig := range config.URLConfigs {
+		if urlConfig.URL == url {
+			return config.urlSettings(urlConfig)
+		}
+	}
+	return config.urlSettings(URLConfig{URL: url})
+}
+
+// getURLStatuses returns the current state of every monitored URL in config order
+func (m *Monitor) getURLStatuses() []URLStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config := m.getConfig()
+	statuses := make([]URLStatus, len(config.URLConfigs))
+	for i, urlConfig := range config.URLConfigs {
+		status := URLStatus{
+			ID:          urlID(urlConfig.URL),
+			URL:         urlConfig.URL,
+			Name:        urlConfig.Name,
+			SearchTerms: urlConfig.SearchTerms,
+			Found:       m.foundURLs[urlConfig.URL],
+			Unreachable: m.unreachableURLs[urlConfig.URL],
+			DownSince:   m.lastURLDownTime[urlConfig.URL],
+			Checking:    m.checksInFlight[urlConfig.URL],
+			Settings:    config.urlSettings(urlConfig),
+			Flapping:    m.flappingURLs[urlConfig.URL],
+			Failures:    m.failureStreaks[urlConfig.URL].Count,
+			SoftError:   m.softErrors[urlConfig.URL],
+			Throttled:   m.throttledUntil[urlConfig.URL],
+		}
+		status.LastCheck = m.perURLCheckTime[urlConfig.URL]
+		status.NextCheck = m.nextCheckTime[urlConfig.URL]
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// getRecentEvents returns events within the recent matches window, oldest first
+func (m *Monitor) getRecentEvents() []EventRecord {
+	events := make([]EventRecord, 0)
 	
 	if m.recentEvents == nil {
-		return matches
+		return events
 	}
 
-	events := m.recentEvents.GetAll()
-	cutoff := time.Now().Add(-time.Duration(m.config.RecentMatchesHours) * time.Hour)
+	cutoff := time.Now().Add(-time.Duration(m.getConfig().RecentMatchesHours) * time.Hour)
 
-	for _, item := range events {
+	for _, item := range m.recentEvents.GetAll() {
 		event, ok := item.(EventRecord)
 		if !ok {
 			continue
@@ -554,6 +961,17 @@ func (m *Monitor) getRecentMatches() []IncidentInfo {
 			continue
 		}
 
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// getRecentMatches returns recent matches for display
+func (m *Monitor) getRecentMatches() []IncidentInfo {
+	matches := make([]IncidentInfo, 0)
+
+	for _, event := range m.getRecentEvents() {
 		match := IncidentInfo{
 			URL:         event.URL,
 			Timestamp:   m.formatLocalTime(event.Timestamp),
@@ -1160,49 +1578,104 @@ func extractAddressWater(htmlContent string, searchTerms []string, url string) s
 	return ""
 }
 
-// handleConnectionFailure handles a URL that is unreachable
+// handleConnectionFailure tracks a failed check
+// The URL is marked unreachable (and error_recipient notified) after failure_threshold consecutive failures,
+// unless it is flapping; an outage that started while flapping is reported once it outlasts flap_window_minutes
 func (m *Monitor) handleConnectionFailure(result URLCheckResult) {
 	m.mu.Lock()
 	wasUnreachable := m.unreachableURLs[result.URL]
-	m.unreachableURLs[result.URL] = true
-	
-	// Record when it went down (if this is the first failure)
-	if !wasUnreachable {
-		m.lastURLDownTime[result.URL] = time.Now()
+	streak := m.failureStreaks[result.URL]
+	if streak.Count == 0 {
+		streak.Since = result.CheckedAt
 	}
-	m.mu.Unlock()
-	
-	// If this is the first failure, send error email (with rate limiting)
-	if !wasUnreachable {
-		if m.canSendErrorEmail(result.URL) {
-			m.sendErrorEmail(result.URL, result.Name, result.Error)
-			m.recordErrorEmail(result.URL)
-			// Save state immediately after sending error email
-			go m.saveState()
+	streak.Count++
+	m.failureStreaks[result.URL] = streak
+
+	if wasUnreachable {
+		// Still down: report the outage now if its error email was held back while flapping
+		// (it went down at its last state change, so once that is outside the window it is not flapping any more)
+		changes := m.stateChanges[result.URL]
+		wentDown := m.lastURLDownTime[result.URL]
+		if len(changes) > 0 {
+			wentDown = changes[len(changes)-1]
+		}
+		window := time.Duration(m.getConfig().FlapWindowMinutes) * time.Minute
+		deferred := !m.errorNotified[result.URL] && time.Since(wentDown) > window
+		if deferred && m.flappingURLs[result.URL] {
+			delete(m.flappingURLs, result.URL)
+			log.Printf("🔀 %s is no longer flapping (down for %s), sending the deferred error email", result.URL, formatDuration(time.Since(m.lastURLDownTime[result.URL])))
 		}
+		m.mu.Unlock()
+		if deferred {
+			m.notifyOutage(result)
+		}
+		return
 	}
+
+	threshold := m.getConfig().FailureThreshold
+	if streak.Count < threshold {
+		m.mu.Unlock()
+		log.Printf("⚠️  %s failed %d/%d consecutive checks, not marked unreachable yet", result.URL, streak.Count, threshold)
+		return
+	}
+
+	// Down since the first failed check of the streak
+	m.unreachableURLs[result.URL] = true
+	m.lastURLDownTime[result.URL] = streak.Since
+	flapping := m.recordStateChange(result.URL, time.Now())
+	m.mu.Unlock()
+
+	m.publishState(result, "unreachable")
+	if flapping {
+		m.addLog(fmt.Sprintf("%s is flapping - error email deferred", result.URL))
+		return
+	}
+	m.notifyOutage(result)
+}
+
+// notifyOutage emails error_recipient that a URL is unreachable, if the error email limit allows
+// The outage counts as reported (so its recovery is emailed too) only once the email went out
+func (m *Monitor) notifyOutage(result URLCheckResult) {
+	if !m.canSendErrorEmail(result.URL) {
+		return
+	}
+	m.sendErrorEmail(result.URL, result.Name, result.Error)
+	m.recordErrorEmail(result.URL)
+	m.mu.Lock()
+	m.errorNotified[result.URL] = true
+	m.mu.Unlock()
+	// Save state immediately after sending error email
+	go m.saveState()
 }
 
 // handleConnectionRecovery handles a URL that recovered from being unreachable
+// A recovery email is only sent if an error email was sent for the outage
 func (m *Monitor) handleConnectionRecovery(result URLCheckResult) {
 	m.mu.Lock()
 	wasUnreachable := m.unreachableURLs[result.URL]
 	downTime := m.lastURLDownTime[result.URL]
+	notified := m.errorNotified[result.URL]
+	delete(m.failureStreaks, result.URL)
 	
 	// Clear unreachable status
 	if wasUnreachable {
 		delete(m.unreachableURLs, result.URL)
 		delete(m.lastURLDownTime, result.URL)
+		delete(m.errorNotified, result.URL)
+		m.recordStateChange(result.URL, time.Now())
 	}
 	m.mu.Unlock()
 	
 	// Send recovery email if it was previously unreachable
 	if wasUnreachable {
+		m.publishState(result, "recovered")
 		duration := time.Since(downTime)
 		log.Printf("✅ URL recovered: %s (was down for %s)", result.URL, formatDuration(duration))
 		m.addLog(fmt.Sprintf("URL recovered: %s (was down for %s)", result.URL, formatDuration(duration)))
 		
-		if m.canSendErrorEmail(result.URL) {
+		if !notified {
+			log.Printf("ℹ️  No error email was sent for this outage of %s, skipping recovery email", result.URL)
+		} else if m.canSendErrorEmail(result.URL) {
 			m.sendRecoveryEmail(result.URL, result.Name, duration)
 			m.recordErrorEmail(result.URL)
 			// Save state immediately after sending recovery email
@@ -1213,7 +1686,7 @@ func (m *Monitor) handleConnectionRecovery(result URLCheckResult) {
 
 // canSendErrorEmail checks if an error email can be sent for this URL
 func (m *Monitor) canSendErrorEmail(url string) bool {
-	if m.config.ErrorRecipient == "" {
+	if m.getConfig().ErrorRecipient == "" {
 		return false
 	}
 	
@@ -1263,24 +1736,15 @@ func (m *Monitor) sendErrorEmail(url, name string, err error) {
 		displayName = url
 	}
 	
-	subject := fmt.Sprintf("🔴 Nestanak-Info - Connection Error: %s", displayName)
-	body := fmt.Sprintf(`Connection Error Detected
-
-URL Name: %s
-URL: %s
-
-Error Details:
-%v
+	locale := m.recipientLocale(m.getConfig().ErrorRecipient)
+	subject := translate(locale, "email.error.subject", displayName)
+	body := translate(locale, "email.error.body", displayName, url, err, m.formatLocalTime(time.Now()))
 
-Timestamp: %s
-
-This URL is currently unreachable. You will receive a recovery notification when the connection is restored.`, displayName, url, err, m.formatLocalTime(time.Now()))
-
-	if sendErr := sendBrevoEmail(m.config, m.config.ErrorRecipient, subject, body); sendErr != nil {
-		log.Printf("Failed to send error email to %s: %v", m.config.ErrorRecipient, sendErr)
+	if sendErr := m.deliverEmail("error", m.getConfig().ErrorRecipient, subject, body, nil); sendErr != nil {
+		log.Printf("Failed to send error email to %s: %v", m.getConfig().ErrorRecipient, sendErr)
 	} else {
-		log.Printf("📧 Error notification sent to %s for %s", m.config.ErrorRecipient, displayName)
-		m.recordEmailNotification(url, name, []string{m.config.ErrorRecipient}, "error", subject)
+		log.Printf("📧 Error notification sent to %s for %s", m.getConfig().ErrorRecipient, displayName)
+		m.recordEmailNotification(url, name, []string{m.getConfig().ErrorRecipient}, 0, "error", subject)
 	}
 }
 
@@ -1291,27 +1755,21 @@ func (m *Monitor) sendRecoveryEmail(url, name string, downtime time.Duration) {
 		displayName = url
 	}
 	
-	subject := fmt.Sprintf("🟢 Nestanak-Info - Connection Restored: %s", displayName)
-	body := fmt.Sprintf(`Connection Restored
-
-URL Name: %s
-URL: %s
+	locale := m.recipientLocale(m.getConfig().ErrorRecipient)
+	subject := translate(locale, "email.recovery.subject", displayName)
+	body := translate(locale, "email.recovery.body", displayName, url, formatDuration(downtime), m.formatLocalTime(time.Now()))
 
-Downtime Duration: %s
-Restored At: %s
-
-The URL is now reachable again and monitoring has resumed.`, displayName, url, formatDuration(downtime), m.formatLocalTime(time.Now()))
-
-	if sendErr := sendBrevoEmail(m.config, m.config.ErrorRecipient, subject, body); sendErr != nil {
-		log.Printf("Failed to send recovery email to %s: %v", m.config.ErrorRecipient, sendErr)
+	if sendErr := m.deliverEmail("recovery", m.getConfig().ErrorRecipient, subject, body, nil); sendErr != nil {
+		log.Printf("Failed to send recovery email to %s: %v", m.getConfig().ErrorRecipient, sendErr)
 	} else {
-		log.Printf("📧 Recovery notification sent to %s for %s", m.config.ErrorRecipient, displayName)
-		m.recordEmailNotification(url, name, []string{m.config.ErrorRecipient}, "recovery", subject)
+		log.Printf("📧 Recovery notification sent to %s for %s", m.getConfig().ErrorRecipient, displayName)
+		m.recordEmailNotification(url, name, []string{m.getConfig().ErrorRecipient}, 0, "recovery", subject)
 	}
 }
 
 // recordEmailNotification records an email notification for display in web UI
-func (m *Monitor) recordEmailNotification(url, name string, recipients []string, emailType, subject string) {
+// recipients are configured addresses; subscribers only counts self-service subscribers
+func (m *Monitor) recordEmailNotification(url, name string, recipients []string, subscribers int, emailType, subject string) {
 	if m.state == nil {
 		return // State not initialized, skip recording
 	}
@@ -1320,16 +1778,28 @@ func (m *Monitor) recordEmailNotification(url, name string, recipients []string,
 	defer m.state.mu.Unlock()
 
 	notification := EmailNotification{
-		Timestamp:  time.Now(),
-		Recipients: recipients,
-		URL:        url,
-		URLName:    name,
-		Type:       emailType,
-		Subject:    subject,
+		Timestamp:   time.Now(),
+		Recipients:  recipients,
+		Subscribers: subscribers,
+		URL:         url,
+		URLName:     name,
+		Type:        emailType,
+		Subject:     subject,
 	}
 
 	m.state.RecentEmailNotifications = append(m.state.RecentEmailNotifications, notification)
 
+	m.events.Publish("notification", sseNotificationEvent{
+		Outcome:     "sent",
+		Type:        emailType,
+		Timestamp:   m.formatLocalTime(notification.Timestamp),
+		URL:         url,
+		URLName:     name,
+		Subject:     subject,
+		Recipients:  recipients,
+		Subscribers: subscribers,
+	})
+
 	// Keep only last 100 notifications
 	if len(m.state.RecentEmailNotifications) > 100 {
 		m.state.RecentEmailNotifications = m.state.RecentEmailNotifications[len(m.state.RecentEmailNotifications)-100:]
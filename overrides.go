@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// envPrefix is prepended to the upper-cased JSON name of a setting to form its environment variable
+const envPrefix = "NESTANAK_"
+
+// Sources reported by -dump-config (environment variables and secret files add their own name)
+const (
+	sourceDefault = "default"
+	sourceFile    = "config file"
+)
+
+// secretSettings are redacted by -dump-config and can be read from a file named by "<setting>_file"
+var secretSettings = []string{"password_hash", "brevo_api_key", "subscription_secret", "metrics_bearer_token"}
+
+// envName returns the environment variable that overrides a setting
+func envName(setting string) string {
+	return envPrefix + strings.ToUpper(setting)
+}
+
+// isSecretSetting reports whether a setting must not be printed
+func isSecretSetting(setting string) bool {
+	for _, secret := range secretSettings {
+		if setting == secret {
+			return true
+		}
+	}
+	return false
+}
+
+// configField returns the Config field with the given JSON name
+func configField(config *Config, setting string) (reflect.Value, bool) {
+	v := reflect.ValueOf(config).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if configFieldName(v.Type().Field(i)) == setting {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setConfigValue parses an environment variable value into a Config field
+// Lists of strings are comma-separated (or a JSON array); url_configs and maps are JSON
+func setConfigValue(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			items := make([]string, 0)
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			field.Set(reflect.ValueOf(items))
+			return nil
+		}
+		return json.Unmarshal([]byte(value), field.Addr().Interface())
+	default:
+		return json.Unmarshal([]byte(value), field.Addr().Interface())
+	}
+	return nil
+}
+
+// applyEnvOverrides sets every setting that has a NESTANAK_* environment variable
+func applyEnvOverrides(config *Config, sources map[string]string) error {
+	v := reflect.ValueOf(config).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		setting := configFieldName(v.Type().Field(i))
+		name := envName(setting)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setConfigValue(v.Field(i), value); err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+		sources[setting] = "env " + name
+	}
+	return nil
+}
+
+// secretFilePath resolves a *_file path; relative paths are looked up in the systemd
+// credentials directory (LoadCredential=) when the service runs with one
+func secretFilePath(path string) string {
+	if dir := os.Getenv("CREDENTIALS_DIRECTORY"); dir != "" && !filepath.IsAbs(path) {
+		return filepath.Join(dir, path)
+	}
+	return path
+}
+
+// resolveSecretFiles reads secrets whose *_file setting is set; the file wins over an inline value
+func resolveSecretFiles(config *Config, sources map[string]string) error {
+	for _, setting := range secretSettings {
+		pathField, ok := configField(config, setting+"_file")
+		if !ok || pathField.String() == "" {
+			continue
+		}
+		path := secretFilePath(pathField.String())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s_file: %v", setting, err)
+		}
+		field, _ := configField(config, setting)
+		field.SetString(strings.TrimRight(string(data), "\r\n"))
+		sources[setting] = "file " + path
+	}
+	return nil
+}
+
+// dumpConfig prints every setting with its effective value and where it came from
+// Proxy passwords and credential request headers (Cookie, Authorization, ...) are redacted like secrets
+func dumpConfig(w io.Writer, config *Config, sources map[string]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tSOURCE\tVALUE")
+
+	redacted := *config
+	redacted.ProxyURL = redactProxyURL(config.ProxyURL)
+	redacted.URLConfigs = make([]URLConfig, len(config.URLConfigs))
+	for i, urlConfig := range config.URLConfigs {
+		urlConfig.Proxy = redactProxyURL(urlConfig.Proxy)
+		urlConfig.Headers = redactHeaders(urlConfig.Headers)
+		redacted.URLConfigs[i] = urlConfig
+	}
+
+	v := reflect.ValueOf(&redacted).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		setting := configFieldName(v.Type().Field(i))
+
+		var value string
+		if isSecretSetting(setting) && v.Field(i).String() != "" {
+			value = `"<redacted>"`
+		} else {
+			// Not HTML-escaped, so "<redacted>" header values print as they are
+			var encoded strings.Builder
+			encoder := json.NewEncoder(&encoded)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(v.Field(i).Interface()); err != nil {
+				value = fmt.Sprintf("%v", v.Field(i).Interface())
+			} else {
+				value = strings.TrimSuffix(encoded.String(), "\n")
+			}
+		}
+
+		source, ok := sources[setting]
+		if !ok {
+			source = sourceDefault
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", setting, source, value)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+)
+
+// cachedPage returns the last fetched page of a URL if it is still valid for the URL's search settings
+// Entries computed with different search terms or another extractor are ignored
+func (m *Monitor) cachedPage(urlConfig URLConfig) *PageCacheEntry {
+	m.mu.RLock()
+	entry := m.pageCache[urlConfig.URL]
+	m.mu.RUnlock()
+
+	if entry == nil || entry.Extractor != urlConfig.extractorType() || !slices.Equal(entry.SearchTerms, urlConfig.SearchTerms) {
+		return nil
+	}
+	return entry
+}
+
+// setConditionalHeaders asks the server to answer 304 Not Modified if the page has not changed
+func setConditionalHeaders(req *http.Request, entry *PageCacheEntry) {
+	if entry == nil {
+		return
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// storePage remembers the validators, body hash and parsed result of a fetched page
+func (m *Monitor) storePage(urlConfig URLConfig, header http.Header, bodyHash [32]byte, result URLCheckResult) {
+	entry := &PageCacheEntry{
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		BodyHash:     bodyHash,
+		SearchTerms:  slices.Clone(urlConfig.SearchTerms),
+		Extractor:    urlConfig.extractorType(),
+		Result:       result,
+	}
+
+	m.mu.Lock()
+	m.pageCache[urlConfig.URL] = entry
+	m.mu.Unlock()
+}
+
+// reuseCachedResult fills a check result from the previous fetch of an unchanged page
+func reuseCachedResult(entry *PageCacheEntry, result URLCheckResult) URLCheckResult {
+	result.Found = entry.Result.Found
+	result.FoundTerms = entry.Result.FoundTerms
+	result.Date = entry.Result.Date
+	result.Time = entry.Result.Time
+	result.Address = entry.Result.Address
+	result.Body = entry.Result.Body
+	result.Unchanged = true
+	return result
+}
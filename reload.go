@@ -0,0 +1,218 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// restartRequiredSettings are read only at startup (listeners, buffers, pools, session manager);
+// a reload keeps their running values and logs that a restart is needed
+var restartRequiredSettings = map[string]bool{
+	"max_concurrent_checks":         true,
+	"dns_cache_ttl_minutes":         true,
+	"user_agent_rotation_enabled":   true,
+	"user_agent_pool_size":          true,
+	"http_enabled":                  true,
+	"http_listen":                   true,
+	"http_log_lines":                true,
+	"http_rate_limit_per_minute":    true,
+	"log_buffer_flush_seconds":      true,
+	"recent_events_buffer_size":     true,
+	"auth_enabled":                  true,
+	"session_timeout_minutes":       true,
+	"max_login_attempts":            true,
+	"lockout_duration_minutes":      true,
+	"state_file_path":               true,
+	"subscriptions_enabled":         true,
+	"metrics_enabled":               true,
+	"metrics_listen":                true,
+	"config_watch_interval_seconds": true,
+}
+
+// configFieldName returns the JSON name of a Config field
+func configFieldName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+// changedConfigSettings lists the settings (by JSON name) that differ between two configs, except url_configs
+func changedConfigSettings(a, b *Config) []string {
+	changed := make([]string, 0)
+	av := reflect.ValueOf(a).Elem()
+	bv := reflect.ValueOf(b).Elem()
+	for i := 0; i < av.NumField(); i++ {
+		name := configFieldName(av.Type().Field(i))
+		if name == "url_configs" {
+			continue
+		}
+		if !reflect.DeepEqual(av.Field(i).Interface(), bv.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// keepRestartRequiredSettings copies restart-only settings from running into reloaded,
+// returning the names of those that were changed in the file
+func keepRestartRequiredSettings(running, reloaded *Config) []string {
+	kept := make([]string, 0)
+	rv := reflect.ValueOf(running).Elem()
+	nv := reflect.ValueOf(reloaded).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		name := configFieldName(rv.Type().Field(i))
+		if !restartRequiredSettings[name] {
+			continue
+		}
+		if !reflect.DeepEqual(rv.Field(i).Interface(), nv.Field(i).Interface()) {
+			nv.Field(i).Set(rv.Field(i))
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// syncURLMonitors starts, stops and restarts check loops so they match updated
+// With restartAll (e.g. the check interval or adaptive_polling changed) every loop is restarted, staggered as at startup
+func (m *Monitor) syncURLMonitors(previous, updated []URLConfig, restartAll bool) {
+	previousByURL := make(map[string]URLConfig)
+	for _, urlConfig := range previous {
+		previousByURL[urlConfig.URL] = urlConfig
+	}
+	kept := make(map[string]bool)
+	for _, urlConfig := range updated {
+		kept[urlConfig.URL] = true
+	}
+
+	for _, urlConfig := range previous {
+		if !kept[urlConfig.URL] {
+			m.stopURLMonitor(urlConfig.URL)
+			m.forgetURL(urlConfig.URL)
+			log.Printf("➖ Stopped monitoring %s", urlConfig.URL)
+			m.addLog(fmt.Sprintf("Stopped monitoring %s", urlConfig.URL))
+		}
+	}
+
+	// New URLs start right away; changed ones restart with the new settings
+	intervalDuration := time.Duration(m.getConfig().CheckIntervalSeconds) * time.Second
+	for i, urlConfig := range updated {
+		old, existed := previousByURL[urlConfig.URL]
+		if existed && urlConfigEqual(old, urlConfig) && !restartAll {
+			continue
+		}
+
+		staggerDelay := time.Duration(0)
+		if existed {
+			m.stopURLMonitor(urlConfig.URL)
+			if restartAll {
+				staggerDelay = (intervalDuration / time.Duration(len(updated))) * time.Duration(i)
+			} else {
+				log.Printf("✏️  Updated monitoring settings for %s", urlConfig.URL)
+				m.addLog(fmt.Sprintf("Updated monitoring settings for %s", urlConfig.URL))
+			}
+		} else {
+			log.Printf("➕ Started monitoring %s", urlConfig.URL)
+			m.addLog(fmt.Sprintf("Started monitoring %s", urlConfig.URL))
+		}
+		m.startURLMonitor(urlConfig, staggerDelay)
+	}
+}
+
+// configFileHash returns the SHA-256 of the config file, used to detect changes
+func configFileHash(filename string) ([32]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// reloadConfig re-reads and validates the config file and applies it without a restart
+// In-memory state (found/unreachable URLs, history, email counters) is kept
+func (m *Monitor) reloadConfig(trigger string) error {
+	m.configMu.Lock()
+	defer m.configMu.Unlock()
+
+	hash, err := configFileHash(m.configPath)
+	if err != nil {
+		log.Printf("⚠️  Config reload (%s) failed: %v", trigger, err)
+		m.addLog(fmt.Sprintf("Config reload (%s) failed: %v", trigger, err))
+		return err
+	}
+
+	reloaded, err := loadConfig(m.configPath)
+	if err == nil {
+		err = ValidateConfig(reloaded)
+	}
+	if err != nil {
+		// Remember the broken file so the watcher does not retry it every poll
+		m.configHash = hash
+		log.Printf("⚠️  Config reload (%s) failed, keeping the running configuration: %v", trigger, err)
+		m.addLog(fmt.Sprintf("Config reload (%s) failed, keeping the running configuration", trigger))
+		return err
+	}
+	m.configHash = hash
+
+	running := m.getConfig()
+	kept := keepRestartRequiredSettings(running, &reloaded)
+	changed := changedConfigSettings(running, &reloaded)
+	urlsChanged := !slices.EqualFunc(running.URLConfigs, reloaded.URLConfigs, urlConfigEqual)
+
+	if len(kept) > 0 {
+		log.Printf("⚠️  Config reload (%s): restart required to apply %s", trigger, strings.Join(kept, ", "))
+		m.addLog(fmt.Sprintf("Restart required to apply %s", strings.Join(kept, ", ")))
+	}
+	if len(changed) == 0 && !urlsChanged {
+		log.Printf("🔄 Config reload (%s): no changes", trigger)
+		return nil
+	}
+
+	m.config.Store(&reloaded)
+	// Loops pick their interval (and whether to adapt it) when they start
+	restartAll := running.CheckIntervalSeconds != reloaded.CheckIntervalSeconds || running.AdaptivePolling != reloaded.AdaptivePolling
+	m.syncURLMonitors(running.URLConfigs, reloaded.URLConfigs, restartAll)
+
+	summary := strings.Join(changed, ", ")
+	if urlsChanged {
+		summary = strings.TrimPrefix(summary+", url_configs", ", ")
+	}
+	log.Printf("🔄 Configuration reloaded (%s): %s", trigger, summary)
+	m.addLog(fmt.Sprintf("Configuration reloaded (%s): %s", trigger, summary))
+	return nil
+}
+
+// watchConfigFile polls the config file and reloads it when its content changes
+func (m *Monitor) watchConfigFile(interval time.Duration) {
+	m.configMu.Lock()
+	if hash, err := configFileHash(m.configPath); err == nil {
+		m.configHash = hash
+	}
+	m.configMu.Unlock()
+
+	log.Printf("👀 Watching %s for changes (every %v)", m.configPath, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			hash, err := configFileHash(m.configPath)
+			if err != nil {
+				continue // Editors may briefly remove the file while saving
+			}
+			m.configMu.Lock()
+			changed := hash != m.configHash
+			m.configMu.Unlock()
+			if changed {
+				m.reloadConfig("file change")
+			}
+		case <-m.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// tlsVersions are the accepted tls_min_version values
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsVersionNames lists tls_min_version values in order (for errors and the admin form)
+var tlsVersionNames = []string{"1.0", "1.1", "1.2", "1.3"}
+
+// hasTLSOptions reports whether a URL has TLS settings of its own
+func (u URLConfig) hasTLSOptions() bool {
+	return u.CAFile != "" || u.PinnedCertSHA256 != "" || u.TLSMinVersion != ""
+}
+
+// validateHeaders checks that custom request headers are valid HTTP header fields
+func validateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("invalid value for header %q", name)
+		}
+	}
+	return nil
+}
+
+// credentialHeaders are request headers whose values are credentials (see isCredentialHeader)
+var credentialHeaders = []string{"Cookie", "Authorization", "Proxy-Authorization"}
+
+// isCredentialHeader reports whether a header carries a credential: a session cookie, an authorization
+// or any header with "token" in its name (e.g. X-Auth-Token)
+func isCredentialHeader(name string) bool {
+	for _, header := range credentialHeaders {
+		if strings.EqualFold(name, header) {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(name), "token")
+}
+
+// redactHeaders returns a copy of request headers with credential values hidden, for -dump-config
+func redactHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if isCredentialHeader(name) && value != "" {
+			value = "<redacted>"
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// applyRequestHeaders sets a URL's custom headers on a request, replacing the browser headers of the same name
+func applyRequestHeaders(req *http.Request, headers map[string]string) {
+	for name, value := range headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+}
+
+// customUserAgent returns the "User-Agent" of a URL's custom headers, or "" if it has none
+func customUserAgent(headers map[string]string) string {
+	for name, value := range headers {
+		if strings.EqualFold(name, "User-Agent") {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseCertPin parses a SHA-256 certificate fingerprint, with or without colons
+// (as printed by "openssl x509 -noout -fingerprint -sha256")
+func parseCertPin(value string) ([sha256.Size]byte, error) {
+	var pin [sha256.Size]byte
+	cleaned := strings.NewReplacer(":", "", " ", "").Replace(value)
+	decoded, err := hex.DecodeString(cleaned)
+	if err != nil || len(decoded) != sha256.Size {
+		return pin, errors.New("pinned_cert_sha256 must be a SHA-256 fingerprint (64 hex digits)")
+	}
+	copy(pin[:], decoded)
+	return pin, nil
+}
+
+// urlTLSConfig builds the TLS settings of a URL: extra trusted CAs, a pinned certificate and a minimum version
+func urlTLSConfig(u URLConfig) (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if u.TLSMinVersion != "" {
+		version, ok := tlsVersions[u.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("tls_min_version must be one of %s", strings.Join(tlsVersionNames, ", "))
+		}
+		config.MinVersion = version
+	}
+
+	// The bundle is trusted in addition to the system roots (e.g. a missing intermediate certificate)
+	if u.CAFile != "" {
+		data, err := os.ReadFile(u.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_file: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("ca_file %s contains no PEM certificates", u.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	// A pinned certificate replaces chain verification, so broken or self-signed chains are accepted
+	// as long as the server presents exactly this certificate
+	if u.PinnedCertSHA256 != "" {
+		pin, err := parseCertPin(u.PinnedCertSHA256)
+		if err != nil {
+			return nil, err
+		}
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("server sent no certificate")
+			}
+			if sha256.Sum256(state.PeerCertificates[0].Raw) != pin {
+				return errors.New("server certificate does not match pinned_cert_sha256")
+			}
+			return nil
+		}
+	}
+
+	return config, nil
+}
+
+// checkTransport returns the transport for a URL: the shared one, or a clone with the URL's TLS settings
+// Clones are kept per URL and rebuilt when its TLS settings change
+func (m *Monitor) checkTransport(u URLConfig) (*http.Transport, error) {
+	if !u.hasTLSOptions() {
+		return m.httpTransport, nil
+	}
+
+	key := strings.Join([]string{u.CAFile, u.PinnedCertSHA256, u.TLSMinVersion}, "\x00")
+	m.mu.RLock()
+	cached, ok := m.urlTransports[u.URL]
+	m.mu.RUnlock()
+	if ok && cached.key == key {
+		return cached.transport, nil
+	}
+
+	tlsConfig, err := urlTLSConfig(u)
+	if err != nil {
+		return nil, err
+	}
+	transport := m.httpTransport.Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	m.mu.Lock()
+	m.urlTransports[u.URL] = urlTransport{key: key, transport: transport}
+	m.mu.Unlock()
+	if ok {
+		cached.transport.CloseIdleConnections()
+	}
+	return transport, nil
+}
+
+// cookieJar returns the cookie jar of a URL with cookie_jar enabled, or nil
+// Cookies set by the site are sent back on later checks (kept in memory only)
+func (m *Monitor) cookieJar(u URLConfig) http.CookieJar {
+	if !u.CookieJar {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jar, ok := m.cookieJars[u.URL]
+	if !ok {
+		jar, _ = cookiejar.New(nil) // Never fails without options
+		m.cookieJars[u.URL] = jar
+	}
+	return jar
+}
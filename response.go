@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// Soft error kinds: the server answered 200, but with a page that is not the monitored content
+const (
+	softErrorMaintenance = "maintenance" // Maintenance or "temporarily unavailable" page
+	softErrorPortal      = "portal"      // Captive portal or login page
+	softErrorEmpty       = "empty"       // Blank page, or tables without any data
+)
+
+// minPageTextRunes is the visible text below which a page is considered empty
+const minPageTextRunes = 50
+
+// defaultAllowedContentTypes are the media types accepted when allowed_content_types is not set
+var defaultAllowedContentTypes = []string{"text/html", "application/xhtml+xml", "text/plain"}
+
+// defaultSoftErrorMarkers are matched (case-insensitively) against the title and main heading of a page
+var defaultSoftErrorMarkers = []string{
+	"maintenance",
+	"temporarily unavailable",
+	"održavanj",
+	"privremeno nedostup",
+	"одржавањ",
+	"привремено недоступ",
+}
+
+// checkContentType rejects responses whose Content-Type is not one of the allowed media types
+// A response without a Content-Type header is accepted
+func checkContentType(header http.Header, allowed []string) error {
+	value := header.Get("Content-Type")
+	if value == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return fmt.Errorf("invalid Content-Type %q", value)
+	}
+	if !slices.Contains(allowed, mediaType) {
+		return fmt.Errorf("unexpected Content-Type %s", mediaType)
+	}
+	return nil
+}
+
+// decodeBody returns a reader for the decompressed body of a response sent with Accept-Encoding set
+// by setBrowserHeaders (the transport only decompresses responses to its own gzip header)
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		// "deflate" should be zlib-wrapped, but some servers send a raw deflate stream
+		buffered := bufio.NewReader(resp.Body)
+		header, err := buffered.Peek(2)
+		if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+	}
+	return nil, fmt.Errorf("unsupported Content-Encoding %s", resp.Header.Get("Content-Encoding"))
+}
+
+// readBody reads the decompressed body of a response, at most maxBytes; larger bodies are an error
+func readBody(resp *http.Response, maxBytes int64) ([]byte, error) {
+	body, err := decodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("response body exceeds max_body_kb (%d KB)", maxBytes/1024)
+	}
+	return data, nil
+}
+
+// sameSite reports whether two hosts are the same site, ignoring a "www." prefix
+func sameSite(a, b string) bool {
+	return strings.TrimPrefix(strings.ToLower(a), "www.") == strings.TrimPrefix(strings.ToLower(b), "www.")
+}
+
+// pageSummary holds what detectSoftError looks at in a page
+type pageSummary struct {
+	title       string
+	heading     string // Text of the first <h1>
+	textRunes   int    // Visible text, excluding whitespace
+	password    bool   // Has a password input
+	dataRows    int    // Table rows with <td> cells
+	filledCells int    // <td> cells with text
+}
+
+// summarizePage walks a page once to collect the parts detectSoftError needs
+func summarizePage(body string) pageSummary {
+	var summary pageSummary
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return summary
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			for _, r := range n.Data {
+				if !unicode.IsSpace(r) {
+					summary.textRunes++
+				}
+			}
+		}
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript", "template":
+				return
+			case "title":
+				summary.title = strings.TrimSpace(nodeText(n))
+			case "h1":
+				if summary.heading == "" {
+					summary.heading = strings.TrimSpace(nodeText(n))
+				}
+			case "input":
+				for _, attr := range n.Attr {
+					if attr.Key == "type" && strings.EqualFold(attr.Val, "password") {
+						summary.password = true
+					}
+				}
+			case "tr":
+				hasCells := false
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					if c.Type == html.ElementNode && c.Data == "td" {
+						hasCells = true
+						if strings.TrimSpace(nodeText(c)) != "" {
+							summary.filledCells++
+						}
+					}
+				}
+				if hasCells {
+					summary.dataRows++
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return summary
+}
+
+// nodeText returns the concatenated text inside a node
+func nodeText(n *html.Node) string {
+	var text strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return text.String()
+}
+
+// detectSoftError recognizes pages that load fine but are not the monitored content: maintenance pages,
+// captive portals and login pages, and blank pages or tables without data
+// It returns the soft error kind (empty if the page looks real) and a short description
+func detectSoftError(body string, requested, final *url.URL, markers []string) (string, string) {
+	if final != nil && !sameSite(requested.Hostname(), final.Hostname()) {
+		return softErrorPortal, fmt.Sprintf("redirected to %s", final.Hostname())
+	}
+
+	summary := summarizePage(body)
+	for _, text := range []string{summary.title, summary.heading} {
+		lower := strings.ToLower(text)
+		for _, marker := range markers {
+			if marker != "" && strings.Contains(lower, strings.ToLower(marker)) {
+				return softErrorMaintenance, fmt.Sprintf("%q", text)
+			}
+		}
+	}
+	if summary.password {
+		return softErrorPortal, "page asks for a password"
+	}
+	if summary.textRunes < minPageTextRunes {
+		return softErrorEmpty, fmt.Sprintf("%d characters of text", summary.textRunes)
+	}
+	if summary.dataRows > 0 && summary.filledCells == 0 {
+		return softErrorEmpty, fmt.Sprintf("no data in %d table rows", summary.dataRows)
+	}
+	return "", ""
+}
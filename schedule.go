@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checkSchedule decides when a URL is checked next
+// Times passed in and returned are in local time (server time shifted by time_offset_hours)
+type checkSchedule interface {
+	next(after time.Time) time.Time // Zero if the schedule never fires again
+}
+
+// scheduleHorizon bounds the search for the next cron match (e.g. "0 0 29 2 *" only fires in leap years)
+const scheduleHorizon = 5 * 366 * 24 * time.Hour
+
+// cronMacros are the shorthand cron expressions accepted in schedules
+var cronMacros = map[string]string{
+	"@hourly": "0 * * * *",
+	"@daily":  "0 0 * * *",
+}
+
+// parseSchedule parses a URL schedule: a 5-field cron expression ("*/10 6-22 * * *")
+// or time-of-day windows ("every 2m between 06:00-23:00, hourly otherwise")
+func parseSchedule(expr string) (checkSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	lower := strings.ToLower(expr)
+	if strings.HasPrefix(lower, "every ") || strings.HasPrefix(lower, "hourly") {
+		return parseWindowSchedule(lower)
+	}
+	if macro, ok := cronMacros[lower]; ok {
+		expr = macro
+	}
+	schedule, err := parseCronSchedule(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject expressions that can never match, such as February 30th
+	now := time.Now()
+	if schedule.next(now).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", expr)
+	}
+	return schedule, nil
+}
+
+// cronSchedule is a standard 5-field cron expression: minute hour day-of-month month day-of-week
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64 // Bit n is set when value n matches
+	domAny, dowAny                bool   // Field was "*" (day matching follows cron's OR rule otherwise)
+}
+
+// cronFieldRanges are the allowed values of each cron field (day-of-week 7 is Sunday, like 0)
+var cronFieldRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// cronFieldNames are used in parse errors
+var cronFieldNames = [5]string{"minute", "hour", "day of month", "month", "day of week"}
+
+// parseCronSchedule parses a 5-field cron expression
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day month weekday)", expr)
+	}
+
+	bits := make([]uint64, 5)
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFieldRanges[i][0], cronFieldRanges[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron %s field %q: %v", cronFieldNames[i], field, err)
+		}
+		bits[i] = b
+	}
+
+	// Sunday may be written as 0 or 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &cronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses one cron field: "*", "5", "1-5", "*/15", "0-30/10" or a comma-separated list of those
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, stepText, found := strings.Cut(part, "/"); found {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			part, step = base, n
+		}
+
+		low, high := min, max
+		if part != "*" {
+			lowText, highText, isRange := strings.Cut(part, "-")
+			var err error
+			if low, err = strconv.Atoi(lowText); err != nil {
+				return 0, fmt.Errorf("invalid value %q", lowText)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highText); err != nil {
+					return 0, fmt.Errorf("invalid value %q", highText)
+				}
+			} else if step > 1 {
+				high = max // "5/15" means from 5 to the end in steps of 15
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("values must be between %d and %d", min, max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// dayMatches applies cron's day rule: when both day fields are restricted, either may match
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowMatch
+	case c.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// next returns the first matching minute strictly after the given time
+func (c *cronSchedule) next(after time.Time) time.Time {
+	loc := after.Location()
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := after.Add(scheduleHorizon)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// windowRule checks every interval, either within a time-of-day window or as the fallback
+type windowRule struct {
+	every      time.Duration
+	start, end int  // Minutes since midnight; the window may wrap past midnight
+	fallback   bool // No window: applies whenever no window rule does
+}
+
+// contains reports whether a minute of the day falls inside the rule's window
+func (r windowRule) contains(minute int) bool {
+	if r.start < r.end {
+		return minute >= r.start && minute < r.end
+	}
+	return minute >= r.start || minute < r.end
+}
+
+// windowSchedule is a list of interval rules tried in order; without a fallback
+// rule nothing is checked outside the windows
+type windowSchedule struct {
+	rules []windowRule
+}
+
+// windowPattern matches "06:00-23:00" or "06:00 and 23:00"
+var windowPattern = regexp.MustCompile(`^(\d{1,2}):(\d{2})\s*(?:-|and)\s*(\d{1,2}):(\d{2})$`)
+
+// parseWindowSchedule parses comma-separated rules: "every 2m between 06:00-23:00", "every 15m otherwise", "hourly"
+func parseWindowSchedule(expr string) (*windowSchedule, error) {
+	schedule := &windowSchedule{}
+	hasFallback := false
+
+	for _, part := range strings.Split(expr, ",") {
+		tokens := strings.Fields(part)
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("empty rule in schedule %q", expr)
+		}
+
+		var rule windowRule
+		switch tokens[0] {
+		case "hourly":
+			rule.every = time.Hour
+			tokens = tokens[1:]
+		case "every":
+			if len(tokens) < 2 {
+				return nil, fmt.Errorf("rule %q is missing an interval", strings.TrimSpace(part))
+			}
+			every, err := time.ParseDuration(tokens[1])
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid interval %q", strings.TrimSpace(part), tokens[1])
+			}
+			if every < 5*time.Second {
+				return nil, fmt.Errorf("rule %q: interval must be at least 5s", strings.TrimSpace(part))
+			}
+			rule.every = every
+			tokens = tokens[2:]
+		default:
+			return nil, fmt.Errorf("rule %q must start with \"every\" or \"hourly\"", strings.TrimSpace(part))
+		}
+
+		switch {
+		case len(tokens) == 0 || (len(tokens) == 1 && tokens[0] == "otherwise"):
+			if hasFallback {
+				return nil, fmt.Errorf("schedule %q has more than one rule without a time window", expr)
+			}
+			rule.fallback = true
+			hasFallback = true
+		case tokens[0] == "between":
+			match := windowPattern.FindStringSubmatch(strings.Join(tokens[1:], " "))
+			if match == nil {
+				return nil, fmt.Errorf("rule %q: window must look like \"between 06:00-23:00\"", strings.TrimSpace(part))
+			}
+			start, err := minuteOfDay(match[1], match[2])
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %v", strings.TrimSpace(part), err)
+			}
+			end, err := minuteOfDay(match[3], match[4])
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %v", strings.TrimSpace(part), err)
+			}
+			if start == end {
+				return nil, fmt.Errorf("rule %q: window start and end are the same", strings.TrimSpace(part))
+			}
+			rule.start, rule.end = start, end
+		default:
+			return nil, fmt.Errorf("rule %q: expected \"between HH:MM-HH:MM\" or \"otherwise\"", strings.TrimSpace(part))
+		}
+
+		schedule.rules = append(schedule.rules, rule)
+	}
+
+	return schedule, nil
+}
+
+// minuteOfDay converts "HH", "MM" to minutes since midnight (24:00 is accepted as end of day)
+func minuteOfDay(hourText, minuteText string) (int, error) {
+	hour, _ := strconv.Atoi(hourText)
+	minute, _ := strconv.Atoi(minuteText)
+	if minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %s:%s", hourText, minuteText)
+	}
+	return (hour*60 + minute) % (24 * 60), nil
+}
+
+// ruleAt returns the rule in effect at a time: the first window containing it, else the fallback
+func (s *windowSchedule) ruleAt(t time.Time) (windowRule, bool) {
+	minute := t.Hour()*60 + t.Minute()
+	for _, rule := range s.rules {
+		if !rule.fallback && rule.contains(minute) {
+			return rule, true
+		}
+	}
+	for _, rule := range s.rules {
+		if rule.fallback {
+			return rule, true
+		}
+	}
+	return windowRule{}, false
+}
+
+// next returns the earlier of one interval of the current rule and the opening of the next window,
+// so a faster window is picked up as soon as it starts
+func (s *windowSchedule) next(after time.Time) time.Time {
+	var next time.Time
+	if rule, ok := s.ruleAt(after); ok {
+		next = after.Add(rule.every)
+	}
+
+	midnight := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+	for _, rule := range s.rules {
+		if rule.fallback {
+			continue
+		}
+		start := midnight.Add(time.Duration(rule.start) * time.Minute)
+		if !start.After(after) {
+			start = time.Date(after.Year(), after.Month(), after.Day()+1, 0, 0, 0, 0, after.Location()).Add(time.Duration(rule.start) * time.Minute)
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// at builds a UTC time for schedule tests
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"step within hours", "*/10 6-22 * * *", at(2026, 10, 18, 5, 55), at(2026, 10, 18, 6, 0)},
+		{"strictly after", "*/10 6-22 * * *", at(2026, 10, 18, 6, 0), at(2026, 10, 18, 6, 10)},
+		{"seconds are dropped", "*/10 6-22 * * *", at(2026, 10, 18, 6, 9).Add(59 * time.Second), at(2026, 10, 18, 6, 10)},
+		{"rolls to next day", "*/10 6-22 * * *", at(2026, 10, 18, 22, 55), at(2026, 10, 19, 6, 0)},
+		{"step from value", "5/15 * * * *", at(2026, 10, 18, 10, 36), at(2026, 10, 18, 10, 50)},
+		{"list", "0,30 12 * * *", at(2026, 10, 18, 12, 0), at(2026, 10, 18, 12, 30)},
+		{"daily macro", "@daily", at(2026, 10, 18, 12, 0), at(2026, 10, 19, 0, 0)},
+		{"hourly macro", "@hourly", at(2026, 10, 18, 12, 0), at(2026, 10, 18, 13, 0)},
+		{"day of month only", "0 9 15 * *", at(2026, 10, 18, 12, 0), at(2026, 11, 15, 9, 0)},
+		{"day of week only", "0 9 * * 1", at(2026, 10, 18, 12, 0), at(2026, 10, 19, 9, 0)},
+		{"sunday as 7", "0 9 * * 7", at(2026, 10, 19, 12, 0), at(2026, 10, 25, 9, 0)},
+		{"sunday as 0", "0 9 * * 0", at(2026, 10, 19, 12, 0), at(2026, 10, 25, 9, 0)},
+		{"either day matches: monday first", "0 9 1 * 1", at(2026, 10, 20, 10, 0), at(2026, 10, 26, 9, 0)},
+		{"either day matches: first of month first", "0 9 1 * 1", at(2026, 10, 26, 10, 0), at(2026, 11, 1, 9, 0)},
+		{"month restriction", "0 0 1 1 *", at(2026, 10, 18, 12, 0), at(2027, 1, 1, 0, 0)},
+		{"leap day", "0 0 29 2 *", at(2026, 3, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("parseSchedule(%q): %v", tt.expr, err)
+			}
+			if got := schedule.next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronScheduleHorizon(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"february 30th", "0 0 30 2 *"},
+		{"april 31st", "0 0 31 4 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCronSchedule(%q): %v", tt.expr, err)
+			}
+			if got := schedule.next(at(2026, 10, 18, 12, 0)); !got.IsZero() {
+				t.Errorf("next = %s, want zero", got)
+			}
+			if _, err := parseSchedule(tt.expr); err == nil {
+				t.Errorf("parseSchedule(%q) accepted an expression that never matches", tt.expr)
+			}
+		})
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []string{
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"every",
+		"every 2x",
+		"every 1s",
+		"every 5m, hourly",
+		"every 2m between 06:00-06:00",
+		"every 2m between 25:00-26:00",
+		"every 2m between 06:60-07:00",
+		"every 2m between 24:30-01:00",
+		"every 2m between six and seven",
+		"every 2m from 06:00-07:00",
+		"every 2m between 06:00-07:00,",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := parseSchedule(expr); err == nil {
+				t.Errorf("parseSchedule(%q) succeeded, want error", expr)
+			}
+		})
+	}
+}
+
+func TestWindowScheduleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"inside window", "every 2m between 06:00-23:00, hourly otherwise", at(2026, 10, 18, 10, 0), at(2026, 10, 18, 10, 2)},
+		{"fallback outside window", "every 2m between 06:00-23:00, hourly otherwise", at(2026, 10, 18, 23, 30), at(2026, 10, 19, 0, 30)},
+		{"window opens before fallback interval", "every 2m between 06:00-23:00, hourly otherwise", at(2026, 10, 18, 5, 30), at(2026, 10, 18, 6, 0)},
+		{"window end is exclusive", "every 2m between 06:00-23:00, hourly otherwise", at(2026, 10, 18, 23, 0), at(2026, 10, 19, 0, 0)},
+		{"fallback listed first", "hourly, every 2m between 06:00 and 23:00", at(2026, 10, 18, 10, 0), at(2026, 10, 18, 10, 2)},
+		{"fallback only", "every 15m", at(2026, 10, 18, 10, 0), at(2026, 10, 18, 10, 15)},
+		{"window crossing midnight, before midnight", "every 5m between 22:00-02:00", at(2026, 10, 18, 23, 0), at(2026, 10, 18, 23, 5)},
+		{"window crossing midnight, after midnight", "every 5m between 22:00-02:00", at(2026, 10, 19, 1, 58), at(2026, 10, 19, 2, 3)},
+		{"no fallback waits for window", "every 5m between 22:00-02:00", at(2026, 10, 19, 3, 0), at(2026, 10, 19, 22, 0)},
+		{"no fallback after window opened today", "every 5m between 08:00-09:00", at(2026, 10, 18, 9, 30), at(2026, 10, 19, 8, 0)},
+		{"window until 24:00", "every 1m between 20:00-24:00", at(2026, 10, 18, 23, 59), at(2026, 10, 19, 0, 0)},
+		{"first window wins", "every 1m between 10:00-11:00, every 10m between 06:00-23:00, hourly otherwise", at(2026, 10, 18, 10, 30), at(2026, 10, 18, 10, 31)},
+		{"later window picked up at its start", "every 1m between 10:00-11:00, every 10m between 06:00-23:00, hourly otherwise", at(2026, 10, 18, 9, 55), at(2026, 10, 18, 10, 0)},
+		{"upper case", "Every 2m Between 06:00-23:00, Hourly Otherwise", at(2026, 10, 18, 10, 0), at(2026, 10, 18, 10, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("parseSchedule(%q): %v", tt.expr, err)
+			}
+			if got := schedule.next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextScheduledCheckOffset(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		interval int
+		offset   int
+		last     time.Time
+		want     time.Time
+	}{
+		{"interval ignores offset", "", 300, 2, at(2026, 10, 18, 3, 30), at(2026, 10, 18, 3, 35)},
+		{"cron without offset", "0 6 * * *", 300, 0, at(2026, 10, 18, 3, 30), at(2026, 10, 18, 6, 0)},
+		{"cron in local time", "0 6 * * *", 300, 2, at(2026, 10, 18, 3, 30), at(2026, 10, 18, 4, 0)},
+		{"cron local day already passed", "0 6 * * *", 300, 2, at(2026, 10, 18, 4, 30), at(2026, 10, 19, 4, 0)},
+		{"negative offset", "0 6 * * *", 300, -5, at(2026, 10, 18, 10, 30), at(2026, 10, 18, 11, 0)},
+		{"window in local time", "every 2m between 06:00-23:00", 300, 2, at(2026, 10, 18, 3, 0), at(2026, 10, 18, 4, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{}
+			m.config.Store(&Config{TimeOffsetHours: tt.offset})
+
+			settings := URLSettings{CheckIntervalSeconds: tt.interval, Schedule: tt.schedule}
+			if tt.schedule != "" {
+				schedule, err := parseSchedule(tt.schedule)
+				if err != nil {
+					t.Fatalf("parseSchedule(%q): %v", tt.schedule, err)
+				}
+				settings.schedule = schedule
+			}
+
+			if got := m.nextScheduledCheck(settings, tt.last); !got.Equal(tt.want) {
+				t.Errorf("nextScheduledCheck = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -18,6 +19,9 @@ func NewServiceState() *ServiceState {
 		LastAlertTimes:             make(map[string]time.Time),
 		SeenMatches:                make(map[string]*MatchRecord),
 		RecentEmailNotifications:   make([]EmailNotification, 0, 100),
+		Subscribers:                make(map[string]*Subscriber),
+		PendingSubscriptions:       make(map[string]*PendingSubscription),
+		Unsubscribed:               make(map[string]time.Time),
 		LastSaved:                  time.Now(),
 	}
 }
@@ -56,6 +60,17 @@ func LoadState(filePath string) *ServiceState {
 		return NewServiceState()
 	}
 
+	// State files written before subscriptions existed may contain null maps
+	if state.Subscribers == nil {
+		state.Subscribers = make(map[string]*Subscriber)
+	}
+	if state.PendingSubscriptions == nil {
+		state.PendingSubscriptions = make(map[string]*PendingSubscription)
+	}
+	if state.Unsubscribed == nil {
+		state.Unsubscribed = make(map[string]time.Time)
+	}
+
 	// Cleanup old data
 	state.CleanupOldData()
 
@@ -163,6 +178,14 @@ func (s *ServiceState) cleanupOldDataUnsafe() {
 			delete(s.SeenMatches, hash)
 		}
 	}
+
+	// Clean up subscription requests that were never confirmed
+	pendingCutoff := now.Add(-subscriptionConfirmWindow)
+	for token, pending := range s.PendingSubscriptions {
+		if pending.RequestedAt.Before(pendingCutoff) {
+			delete(s.PendingSubscriptions, token)
+		}
+	}
 }
 
 // GenerateMatchHash creates a unique hash for an incident
@@ -303,6 +326,108 @@ func (s *ServiceState) RecordAlertTime(url, alertType string) {
 	s.LastAlertTimes[key] = time.Now()
 }
 
+// AddPendingSubscription stores a subscription request and returns its confirmation token
+// An existing pending request for the same email is replaced
+func (s *ServiceState) AddPendingSubscription(email string, terms []string, language string) (string, error) {
+	token, err := generateToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(email)
+	for existingToken, pending := range s.PendingSubscriptions {
+		if strings.ToLower(pending.Email) == key {
+			delete(s.PendingSubscriptions, existingToken)
+		}
+	}
+
+	s.PendingSubscriptions[token] = &PendingSubscription{
+		Email:       email,
+		Terms:       terms,
+		Language:    language,
+		RequestedAt: time.Now(),
+	}
+	return token, nil
+}
+
+// GetPendingSubscription returns the pending request for email, if any
+func (s *ServiceState) GetPendingSubscription(email string) (PendingSubscription, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := strings.ToLower(email)
+	for _, pending := range s.PendingSubscriptions {
+		if strings.ToLower(pending.Email) == key {
+			return *pending, true
+		}
+	}
+	return PendingSubscription{}, false
+}
+
+// ConfirmSubscription turns a pending request into a confirmed subscriber
+// Confirming again for an existing subscriber replaces their terms and language
+func (s *ServiceState) ConfirmSubscription(token string) (*Subscriber, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, exists := s.PendingSubscriptions[token]
+	if !exists || time.Since(pending.RequestedAt) > subscriptionConfirmWindow {
+		return nil, false
+	}
+	delete(s.PendingSubscriptions, token)
+
+	key := strings.ToLower(pending.Email)
+	now := time.Now()
+	subscriber, exists := s.Subscribers[key]
+	if !exists {
+		subscriber = &Subscriber{Email: pending.Email, CreatedAt: pending.RequestedAt}
+		s.Subscribers[key] = subscriber
+	}
+	subscriber.Terms = pending.Terms
+	subscriber.Language = pending.Language
+	subscriber.ConfirmedAt = now
+
+	// Subscribing again lifts an earlier opt-out of a config recipient
+	delete(s.Unsubscribed, key)
+
+	result := *subscriber
+	return &result, true
+}
+
+// Unsubscribe removes a subscriber and records the opt-out so config recipients are skipped too
+func (s *ServiceState) Unsubscribe(email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(email)
+	delete(s.Subscribers, key)
+	s.Unsubscribed[key] = time.Now()
+}
+
+// IsUnsubscribed checks if an email address has opted out of alerts
+func (s *ServiceState) IsUnsubscribed(email string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, exists := s.Unsubscribed[strings.ToLower(email)]
+	return exists
+}
+
+// GetSubscribers returns a copy of all confirmed subscribers
+func (s *ServiceState) GetSubscribers() []Subscriber {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscribers := make([]Subscriber, 0, len(s.Subscribers))
+	for _, subscriber := range s.Subscribers {
+		subscribers = append(subscribers, *subscriber)
+	}
+	return subscribers
+}
+
 // GetStats returns statistics about the current state
 func (s *ServiceState) GetStats() map[string]interface{} {
 	s.mu.RLock()
@@ -323,6 +448,8 @@ func (s *ServiceState) GetStats() map[string]interface{} {
 		"urls_tracked":             len(s.EmailsSentPerURLToday),
 		"total_emails_sent_24h":    totalEmailsSent,
 		"total_error_emails_24h":   totalErrorEmailsSent,
+		"subscribers_count":        len(s.Subscribers),
+		"pending_subscriptions":    len(s.PendingSubscriptions),
 		"last_saved":               s.LastSaved.Format("2006-01-02 15:04:05"),
 	}
 }
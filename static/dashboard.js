@@ -0,0 +1,217 @@
+// Live dashboard updates over Server-Sent Events (/events).
+// Builds DOM nodes with textContent only; the CSP requires Trusted Types for script sinks.
+(function () {
+	'use strict';
+
+	var body = document.body;
+	var labels = body.dataset;
+	var liveStatus = document.getElementById('live-status');
+	var maxItems = 100;
+
+	if (!window.EventSource || !labels.eventsUrl) {
+		return;
+	}
+
+	function format(template, value) {
+		return template.replace('%s', value);
+	}
+
+	function el(tag, style, text) {
+		var node = document.createElement(tag);
+		if (style) {
+			node.style.cssText = style;
+		}
+		if (text !== undefined) {
+			node.textContent = text;
+		}
+		return node;
+	}
+
+	function findURLItem(url) {
+		var items = document.querySelectorAll('.url-item[data-url]');
+		for (var i = 0; i < items.length; i++) {
+			if (items[i].dataset.url === url) {
+				return items[i];
+			}
+		}
+		return null;
+	}
+
+	function setLive(connected) {
+		if (!liveStatus) {
+			return;
+		}
+		liveStatus.textContent = connected ? labels.labelLive : labels.labelReconnecting;
+		liveStatus.classList.toggle('connected', connected);
+	}
+
+	function renderBadge(container, found, unreachable) {
+		var badge = el('span');
+		if (unreachable) {
+			badge.className = 'badge badge-unreachable';
+			badge.textContent = labels.labelUnreachable;
+		} else if (found) {
+			badge.className = 'badge badge-found';
+			badge.textContent = labels.labelFound;
+		} else {
+			badge.className = 'badge badge-ok';
+			badge.textContent = labels.labelOk;
+		}
+		container.replaceChildren(badge);
+	}
+
+	function trimList(list, fromStart) {
+		while (list.children.length > maxItems) {
+			list.removeChild(fromStart ? list.firstElementChild : list.lastElementChild);
+		}
+	}
+
+	function onCheck(data) {
+		var item = findURLItem(data.url);
+		if (item) {
+			var last = item.querySelector('[data-field="last"]');
+			var next = item.querySelector('[data-field="next"]');
+			var throttled = item.querySelector('[data-field="throttled"]');
+			var status = item.querySelector('[data-field="status"]');
+			if (last) {
+				last.textContent = format(labels.formatLast, data.last_check);
+			}
+			if (next) {
+				next.textContent = format(labels.formatNext, data.next_check);
+			}
+			if (throttled) {
+				throttled.hidden = !data.throttled_until;
+				throttled.textContent = format(labels.formatThrottled, data.throttled_until || '');
+			}
+			var checkButton = item.querySelector('.check-form button');
+			if (checkButton) {
+				// Manual checks are refused while the site asked to wait
+				checkButton.disabled = !!data.throttled_until;
+			}
+			if (status) {
+				renderBadge(status, data.found, data.unreachable);
+			}
+		}
+
+		var lastCheck = document.getElementById('stat-last-check');
+		var nextCheck = document.getElementById('stat-next-check');
+		if (lastCheck) {
+			lastCheck.textContent = data.last_check;
+		}
+		if (nextCheck) {
+			nextCheck.textContent = data.next_check;
+		}
+	}
+
+	function onState(data) {
+		var item = findURLItem(data.url);
+		if (!item) {
+			return;
+		}
+		var status = item.querySelector('[data-field="status"]');
+		if (!status) {
+			return;
+		}
+		if (data.state === 'unreachable') {
+			renderBadge(status, false, true);
+		} else if (data.state === 'found') {
+			renderBadge(status, true, false);
+		} else if (data.state === 'not_found' || data.state === 'recovered') {
+			renderBadge(status, false, false);
+		}
+	}
+
+	function onIncident(data) {
+		var section = document.getElementById('recent-matches');
+		var list = document.getElementById('recent-matches-list');
+		if (!section || !list) {
+			return;
+		}
+
+		var resolved = data.resolved;
+		var item = el('div', 'background: ' + (resolved ? '#1e3a1e' : '#2a1a1a') +
+			'; border-left: 3px solid ' + (resolved ? '#4CAF50' : '#f44336') + '; padding-left: 12px;');
+		item.className = 'url-item';
+
+		var info = el('div', 'width: 100%;');
+		info.className = 'url-info';
+		info.appendChild(el('span', 'color: #999; font-size: 11px; display: block; margin-bottom: 3px;', data.timestamp));
+
+		var link = el('a', 'color: ' + (resolved ? '#81c784' : '#f48fb1') + '; display: block; margin-bottom: 4px;', data.url);
+		link.href = data.url;
+		link.target = '_blank';
+		info.appendChild(link);
+
+		info.appendChild(el('span', 'color: ' + (resolved ? '#81c784' : '#ff8a80') + '; font-size: 12px; display: block;', data.description));
+		info.appendChild(el('span', 'color: ' + (resolved ? '#4CAF50' : '#FF9800') + '; font-size: 11px; display: block; margin-top: 4px;',
+			resolved ? labels.labelResolved : labels.labelOngoing));
+
+		item.appendChild(info);
+		list.appendChild(item);
+		trimList(list, true);
+		section.hidden = false;
+	}
+
+	function onNotification(data) {
+		var section = document.getElementById('email-notifications');
+		var list = document.getElementById('email-notifications-list');
+		if (!section || !list) {
+			return;
+		}
+
+		var colors = { match: ['#1a2a3a', '#2196F3'], error: ['#3a1a1a', '#f44336'], structure: ['#3a2a1a', '#FF9800'] };
+		var typeLabels = { match: labels.labelTypeMatch, error: labels.labelTypeError, structure: labels.labelTypeStructure };
+		var color = data.outcome === 'failed' ? colors.error : (colors[data.type] || ['#1a3a1a', '#4CAF50']);
+
+		var item = el('div', 'background: ' + color[0] + '; border-left: 3px solid ' + color[1] + '; padding-left: 12px;');
+		item.className = 'url-item';
+
+		var info = el('div', 'width: 100%;');
+		info.className = 'url-info';
+
+		var header = el('div', 'display: flex; justify-content: space-between; align-items: center; margin-bottom: 6px;');
+		header.appendChild(el('span', 'color: #999; font-size: 11px;', data.timestamp));
+		var badge = el('span', 'background: ' + color[1] + ';', typeLabels[data.type] || labels.labelTypeRecovery);
+		badge.className = 'badge';
+		header.appendChild(badge);
+		info.appendChild(header);
+
+		if (data.outcome === 'failed') {
+			info.appendChild(el('div', 'color: #ff8a80; font-size: 12px;', '⚠️ ' + data.error));
+		} else {
+			info.appendChild(el('div', 'color: #e0e0e0; font-size: 13px; margin-bottom: 4px; font-weight: 500;', data.subject));
+			if (data.url_name) {
+				info.appendChild(el('div', 'color: #999; font-size: 11px; margin-bottom: 4px;', '📍 ' + data.url_name));
+			}
+			var sentTo = labels.labelSentTo + ' ' + (data.recipients || []).join(', ');
+			if (data.subscribers) {
+				sentTo += ' ' + format(labels.formatSubscribers, data.subscribers);
+			}
+			info.appendChild(el('div', 'color: #81c784; font-size: 11px;', sentTo));
+		}
+
+		item.appendChild(info);
+		list.insertBefore(item, list.firstChild);
+		trimList(list, false);
+		section.hidden = false;
+	}
+
+	function listen(source, name, handler) {
+		source.addEventListener(name, function (event) {
+			try {
+				handler(JSON.parse(event.data));
+			} catch (err) {
+				console.warn('nestanak-info: bad ' + name + ' event', err);
+			}
+		});
+	}
+
+	var source = new EventSource(labels.eventsUrl);
+	source.onopen = function () { setLive(true); };
+	source.onerror = function () { setLive(false); };
+
+	listen(source, 'check', onCheck);
+	listen(source, 'state', onState);
+	listen(source, 'incident', onIncident);
+	listen(source, 'notification', onNotification);
+})();
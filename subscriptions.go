@@ -0,0 +1,354 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// subscriptionConfirmWindow is how long a confirmation link stays valid
+const subscriptionConfirmWindow = 48 * time.Hour
+
+// subscriptionResendDelay prevents the form from being used to flood an inbox
+const subscriptionResendDelay = 10 * time.Minute
+
+// maxSubscriptionTerms limits how many area terms a subscriber can register
+const maxSubscriptionTerms = 10
+
+// alertRecipient is a single recipient of a match alert with their language
+type alertRecipient struct {
+	Email      string
+	Locale     string
+	Subscriber bool // Self-service subscriber; the address is never shown in notifications
+}
+
+// subscribePageData is the template data for subscribe.html
+type subscribePageData struct {
+	Lang    string
+	Locales []string
+	Page    string // "form", "check_email", "confirmed", "unsubscribe", "unsubscribed", "invalid"
+	Error   string
+	Email   string
+	Terms   string
+	Token   string
+}
+
+// generateToken returns a random URL-safe token of size bytes
+func generateToken(size int) (string, error) {
+	tokenBytes := make([]byte, size)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// signUnsubscribeToken creates a token that proves the link was issued for email
+// Format: base64url(email) "." base64url(HMAC-SHA256(secret, "unsubscribe:" + lowercase email))
+func signUnsubscribeToken(secret, email string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("unsubscribe:" + strings.ToLower(email)))
+	return base64.RawURLEncoding.EncodeToString([]byte(email)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyUnsubscribeToken checks a signed token and returns the email it was issued for
+func verifyUnsubscribeToken(secret, token string) (string, bool) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	emailBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
+	}
+	email := string(emailBytes)
+	expected := signUnsubscribeToken(secret, email)
+	if !hmac.Equal([]byte(expected), []byte(token)) {
+		return "", false
+	}
+	return email, true
+}
+
+// unsubscribeURL returns the signed one-click unsubscribe link for email
+// Returns empty string when subscriptions are disabled
+func (m *Monitor) unsubscribeURL(email string) string {
+	if !m.getConfig().SubscriptionsEnabled {
+		return ""
+	}
+	values := url.Values{}
+	values.Set("token", signUnsubscribeToken(m.getConfig().SubscriptionSecret, email))
+	return m.getConfig().PublicBaseURL + "/unsubscribe?" + values.Encode()
+}
+
+// alertRecipients returns everyone who should receive a match alert:
+// config recipients (the URL's own list if it has one) that have not opted out
+// plus confirmed subscribers whose terms match
+func (m *Monitor) alertRecipients(result URLCheckResult) []alertRecipient {
+	configRecipients := m.settingsForURL(result.URL).Recipients
+	recipients := make([]alertRecipient, 0, len(configRecipients))
+	seen := make(map[string]bool)
+
+	for _, email := range configRecipients {
+		key := strings.ToLower(email)
+		if seen[key] || (m.state != nil && m.state.IsUnsubscribed(email)) {
+			continue
+		}
+		seen[key] = true
+		recipients = append(recipients, alertRecipient{Email: email, Locale: m.recipientLocale(email)})
+	}
+
+	if !m.getConfig().SubscriptionsEnabled || m.state == nil {
+		return recipients
+	}
+
+	for _, subscriber := range m.state.GetSubscribers() {
+		key := strings.ToLower(subscriber.Email)
+		if seen[key] || !subscriberWantsAlert(subscriber.Terms, result) {
+			continue
+		}
+		seen[key] = true
+		locale := normalizeLocale(subscriber.Language)
+		if locale == "" {
+			locale = m.getConfig().DefaultLanguage
+		}
+		recipients = append(recipients, alertRecipient{Email: subscriber.Email, Locale: locale, Subscriber: true})
+	}
+
+	return recipients
+}
+
+// subscriberWantsAlert checks if any of the subscriber's area terms appear in the extracted address
+// Subscribers without terms get every alert; so does everyone when no address could be extracted
+func subscriberWantsAlert(terms []string, result URLCheckResult) bool {
+	if len(terms) == 0 || result.Address == "" {
+		return true
+	}
+
+	addressLower := strings.ToLower(result.Address)
+	for _, term := range terms {
+		for _, variant := range getSearchVariants(term) {
+			if strings.Contains(addressLower, strings.ToLower(variant)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseSubscriptionTerms splits comma-separated area terms from the subscribe form
+func parseSubscriptionTerms(input string) ([]string, error) {
+	terms := make([]string, 0)
+	for _, term := range strings.Split(input, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if len([]rune(term)) > 100 {
+			return nil, fmt.Errorf("term too long")
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) > maxSubscriptionTerms {
+		return nil, fmt.Errorf("too many terms")
+	}
+	return terms, nil
+}
+
+// allowSubscriptionEmail checks the hourly confirmation email limit and reserves a slot if available
+// Confirmation emails have their own budget so the public form can never use up the one alerts need
+func (m *Monitor) allowSubscriptionEmail() bool {
+	m.emailMu.Lock()
+	defer m.emailMu.Unlock()
+
+	oneHourAgo := time.Now().Add(-time.Hour)
+	validEmails := make([]time.Time, 0)
+	for _, t := range m.subscriptionEmailsThisHour {
+		if t.After(oneHourAgo) {
+			validEmails = append(validEmails, t)
+		}
+	}
+	m.subscriptionEmailsThisHour = validEmails
+
+	if len(m.subscriptionEmailsThisHour) >= m.getConfig().SubscriptionEmailsPerHour {
+		return false
+	}
+	m.subscriptionEmailsThisHour = append(m.subscriptionEmailsThisHour, time.Now())
+	return true
+}
+
+// sendConfirmationEmail sends the double opt-in link to a new subscriber
+func (m *Monitor) sendConfirmationEmail(email, locale, token string, terms []string) error {
+	values := url.Values{}
+	values.Set("token", token)
+	confirmURL := m.getConfig().PublicBaseURL + "/subscribe/confirm?" + values.Encode()
+
+	termsStr := strings.Join(terms, ", ")
+	if termsStr == "" {
+		termsStr = translate(locale, "subscribe.all_areas")
+	}
+
+	subject := translate(locale, "email.confirm.subject")
+	body := translate(locale, "email.confirm.body", termsStr, confirmURL, int(subscriptionConfirmWindow.Hours()))
+	return m.deliverEmail("confirmation", email, subject, body, nil)
+}
+
+// renderSubscribePage renders subscribe.html with the given status code
+func (m *Monitor) renderSubscribePage(w http.ResponseWriter, status int, data subscribePageData) {
+	data.Locales = supportedLocales
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := m.templates.ExecuteTemplate(w, "subscribe.html", data); err != nil {
+		log.Printf("⚠️  Template error: %v", err)
+	}
+}
+
+// handleSubscribe shows the public subscription form and processes submissions
+func (m *Monitor) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	lang := m.uiLocale(w, r)
+
+	if r.Method == http.MethodGet {
+		m.renderSubscribePage(w, http.StatusOK, subscribePageData{Lang: lang, Page: "form"})
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := subscribePageData{
+		Lang:  lang,
+		Page:  "form",
+		Email: strings.TrimSpace(r.FormValue("email")),
+		Terms: r.FormValue("terms"),
+	}
+
+	// Validate input
+	address, err := mail.ParseAddress(data.Email)
+	if err != nil || address.Address != data.Email || len(data.Email) > 254 {
+		data.Error = translate(lang, "subscribe.error.email")
+		m.renderSubscribePage(w, http.StatusBadRequest, data)
+		return
+	}
+	terms, err := parseSubscriptionTerms(data.Terms)
+	if err != nil {
+		data.Error = translate(lang, "subscribe.error.terms", maxSubscriptionTerms)
+		m.renderSubscribePage(w, http.StatusBadRequest, data)
+		return
+	}
+	locale := normalizeLocale(r.FormValue("language"))
+	if locale == "" {
+		locale = lang
+	}
+
+	// The response is identical whether or not an email goes out (including when the list is full),
+	// so the form can't be used to find out who is subscribed
+	checkEmail := subscribePageData{Lang: lang, Page: "check_email", Email: data.Email}
+
+	// Enforce subscriber limit (existing subscribers may still update their terms)
+	isExisting := false
+	subscribers := m.state.GetSubscribers()
+	for _, subscriber := range subscribers {
+		if strings.EqualFold(subscriber.Email, data.Email) {
+			isExisting = true
+			break
+		}
+	}
+	if !isExisting && len(subscribers) >= m.getConfig().MaxSubscribers {
+		log.Printf("⚠️  Subscriber limit reached (%d), not sending a confirmation to %s", m.getConfig().MaxSubscribers, data.Email)
+		m.renderSubscribePage(w, http.StatusOK, checkEmail)
+		return
+	}
+
+	if pending, exists := m.state.GetPendingSubscription(data.Email); exists && time.Since(pending.RequestedAt) < subscriptionResendDelay {
+		log.Printf("ℹ️  Subscription confirmation for %s already sent recently, not resending", data.Email)
+		m.renderSubscribePage(w, http.StatusOK, checkEmail)
+		return
+	}
+
+	if !m.allowSubscriptionEmail() {
+		log.Printf("⚠️  Subscription email rate limit reached (%d/hour), rejecting subscription request", m.getConfig().SubscriptionEmailsPerHour)
+		data.Error = translate(lang, "subscribe.error.busy")
+		m.renderSubscribePage(w, http.StatusTooManyRequests, data)
+		return
+	}
+
+	token, err := m.state.AddPendingSubscription(data.Email, terms, locale)
+	if err != nil {
+		http.Error(w, "Failed to create subscription", http.StatusInternalServerError)
+		return
+	}
+
+	if err := m.sendConfirmationEmail(data.Email, locale, token, terms); err != nil {
+		log.Printf("⚠️  Failed to send subscription confirmation to %s: %v", data.Email, err)
+		data.Error = translate(lang, "subscribe.error.send")
+		m.renderSubscribePage(w, http.StatusBadGateway, data)
+		return
+	}
+
+	log.Printf("📧 Subscription confirmation sent to %s", data.Email)
+	m.addLog(fmt.Sprintf("Subscription confirmation sent to %s", data.Email))
+	go m.saveState()
+
+	m.renderSubscribePage(w, http.StatusOK, checkEmail)
+}
+
+// handleSubscribeConfirm confirms a pending subscription from the emailed link
+func (m *Monitor) handleSubscribeConfirm(w http.ResponseWriter, r *http.Request) {
+	lang := m.uiLocale(w, r)
+
+	subscriber, ok := m.state.ConfirmSubscription(r.URL.Query().Get("token"))
+	if !ok {
+		m.renderSubscribePage(w, http.StatusNotFound, subscribePageData{Lang: lang, Page: "invalid"})
+		return
+	}
+
+	log.Printf("✅ Subscription confirmed for %s (terms: %v)", subscriber.Email, subscriber.Terms)
+	m.addLog(fmt.Sprintf("Subscription confirmed for %s", subscriber.Email))
+	go m.saveState()
+
+	m.renderSubscribePage(w, http.StatusOK, subscribePageData{
+		Lang:  lang,
+		Page:  "confirmed",
+		Email: subscriber.Email,
+		Terms: strings.Join(subscriber.Terms, ", "),
+	})
+}
+
+// handleUnsubscribe handles signed unsubscribe links
+// GET shows a confirmation button (so link scanners can't unsubscribe people);
+// POST unsubscribes, which also serves RFC 8058 one-click requests from mail clients
+func (m *Monitor) handleUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	lang := m.uiLocale(w, r)
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = r.FormValue("token")
+	}
+	email, ok := verifyUnsubscribeToken(m.getConfig().SubscriptionSecret, token)
+	if !ok {
+		m.renderSubscribePage(w, http.StatusNotFound, subscribePageData{Lang: lang, Page: "invalid"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		m.renderSubscribePage(w, http.StatusOK, subscribePageData{Lang: lang, Page: "unsubscribe", Email: email, Token: token})
+	case http.MethodPost:
+		m.state.Unsubscribe(email)
+		log.Printf("👋 Unsubscribed %s", email)
+		m.addLog(fmt.Sprintf("Unsubscribed %s", email))
+		go m.saveState()
+		m.renderSubscribePage(w, http.StatusOK, subscribePageData{Lang: lang, Page: "unsubscribed", Email: email})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryAfterDelay returns how long a 429 or 503 response asks the client to wait, at most maxDelay
+// Retry-After is either a number of seconds or an HTTP date; ok is false if it is missing or invalid
+func retryAfterDelay(header http.Header, now time.Time, maxDelay time.Duration) (time.Duration, bool) {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	// Capped before converting to a Duration, so huge values cannot overflow into a negative delay
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(min(seconds, int64(maxDelay/time.Second))) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return min(max(date.Sub(now), 0), maxDelay), true
+	}
+	return 0, false
+}
+
+// throttleDelay decides whether a response is the site asking to slow down, and for how long
+// 429 always is (retry_after_default_seconds without a usable Retry-After); 503 only with Retry-After,
+// otherwise it is an outage. The delay is capped at retry_after_max_seconds
+func (m *Monitor) throttleDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	config := m.getConfig()
+	maxDelay := time.Duration(config.RetryAfterMaxSeconds) * time.Second
+	delay, ok := retryAfterDelay(resp.Header, now, maxDelay)
+	if !ok {
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			return 0, false
+		}
+		delay = time.Duration(config.RetryAfterDefaultSeconds) * time.Second
+	}
+	return min(delay, maxDelay), true
+}
+
+// handleThrottled records that a site asked to wait before the next check of a URL
+// Throttling is not a failure: the failure streak, unreachable state and error emails are left alone
+func (m *Monitor) handleThrottled(result URLCheckResult) {
+	until := result.CheckedAt.Add(result.RetryAfter)
+
+	m.mu.Lock()
+	wasThrottled := !m.throttledUntil[result.URL].IsZero()
+	m.throttledUntil[result.URL] = until
+	if next := m.nextCheckTime[result.URL]; !next.IsZero() && next.Before(until) {
+		m.nextCheckTime[result.URL] = until
+	}
+	m.mu.Unlock()
+
+	log.Printf("⏸️  %s asked to wait (HTTP %d), pausing checks until %s", result.URL, result.StatusCode, m.formatLocalTime(until))
+	if !wasThrottled {
+		m.addLog(fmt.Sprintf("%s asked to wait (HTTP %d), pausing checks until %s", result.URL, result.StatusCode, m.formatLocalTime(until)))
+	}
+}
+
+// clearThrottle ends the backoff of a URL after a check that was not throttled
+func (m *Monitor) clearThrottle(url string) {
+	m.mu.Lock()
+	wasThrottled := !m.throttledUntil[url].IsZero()
+	delete(m.throttledUntil, url)
+	m.mu.Unlock()
+
+	if wasThrottled {
+		log.Printf("▶️  %s no longer asks to wait, checks resume", url)
+	}
+}
+
+// checkPausedError is returned by triggerCheck while the site asked to wait
+type checkPausedError struct {
+	until time.Time
+}
+
+// Error describes the pause, including when it ends
+func (e checkPausedError) Error() string {
+	return "checks paused until " + e.until.UTC().Format(time.RFC3339) + " (site asked to wait)"
+}
+
+// pausedUntil returns when a URL's backoff ends, or zero if the site has not asked to wait
+func (m *Monitor) pausedUntil(url string) time.Time {
+	m.mu.RLock()
+	until := m.throttledUntil[url]
+	m.mu.RUnlock()
+
+	if !time.Now().Before(until) {
+		return time.Time{}
+	}
+	return until
+}
+
+// deferForThrottle moves a URL's next check to the end of its backoff; zero (no further runs) stays zero
+func (m *Monitor) deferForThrottle(url string, next time.Time) time.Time {
+	until := m.pausedUntil(url)
+	if next.IsZero() || !next.Before(until) {
+		return next
+	}
+	return until
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterDelay(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	maxDelay := time.Hour
+
+	tests := []struct {
+		name   string
+		value  string // Retry-After header; empty = not sent
+		want   time.Duration
+		wantOK bool
+	}{
+		{"missing", "", 0, false},
+		{"delta seconds", "120", 2 * time.Minute, true},
+		{"zero", "0", 0, true},
+		{"surrounding spaces", " 30 ", 30 * time.Second, true},
+		{"capped at max", "7200", maxDelay, true},
+		{"out of range positive", "99999999999999999999", maxDelay, true},
+		{"beyond duration range", "9223372036854775807", maxDelay, true},
+		{"negative", "-5", 0, false},
+		{"out of range negative", "-99999999999999999999", 0, false},
+		{"date in the future", "Sun, 18 Oct 2026 12:10:00 GMT", 10 * time.Minute, true},
+		{"date beyond max", "Mon, 19 Oct 2026 12:00:00 GMT", maxDelay, true},
+		{"date in the past", "Sun, 18 Oct 2026 11:00:00 GMT", 0, true},
+		{"garbage", "soon", 0, false},
+		{"fraction", "1.5", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			got, ok := retryAfterDelay(header, now, maxDelay)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfterDelay(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestThrottleDelay(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		status        int
+		retryAfter    string
+		defaultSecs   int
+		maxSecs       int
+		want          time.Duration
+		wantThrottled bool
+	}{
+		{"ok response", http.StatusOK, "60", 300, 3600, 0, false},
+		{"server error", http.StatusInternalServerError, "60", 300, 3600, 0, false},
+		{"429 with retry-after", http.StatusTooManyRequests, "60", 300, 3600, time.Minute, true},
+		{"429 without retry-after uses default", http.StatusTooManyRequests, "", 300, 3600, 5 * time.Minute, true},
+		{"429 with garbage uses default", http.StatusTooManyRequests, "later", 300, 3600, 5 * time.Minute, true},
+		{"429 capped at max", http.StatusTooManyRequests, "86400", 300, 600, 10 * time.Minute, true},
+		{"default capped at max", http.StatusTooManyRequests, "", 900, 600, 10 * time.Minute, true},
+		{"503 with retry-after", http.StatusServiceUnavailable, "120", 300, 3600, 2 * time.Minute, true},
+		{"503 without retry-after is an outage", http.StatusServiceUnavailable, "", 300, 3600, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{}
+			m.config.Store(&Config{RetryAfterDefaultSeconds: tt.defaultSecs, RetryAfterMaxSeconds: tt.maxSecs})
+
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			got, throttled := m.throttleDelay(resp, now)
+			if got != tt.want || throttled != tt.wantThrottled {
+				t.Errorf("throttleDelay = %s, %v, want %s, %v", got, throttled, tt.want, tt.wantThrottled)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,12 +23,22 @@ type URLCheckResult struct {
 	Found        bool
 	FoundTerms   []string
 	SearchTerms  []string // The search terms used
+	Extractor    string   // Extractor the details were extracted with ("power" or "water", see URLConfig.extractorType)
 	Date         string   // Extracted date
 	Time         string   // Extracted time
 	Address      string   // Extracted address
 	Error        error
 	CheckedAt    time.Time
 	ResponseTime time.Duration
+	StatusCode   int           // HTTP status code (0 if no response was received)
+	Body         string        // Response body (kept for the URL detail page, not persisted)
+	Manual       bool          // Triggered via "check now"
+	Unchanged    bool          // Page not modified since the last fetch (HTTP 304 or same body); parsed fields are reused
+	Retries      int           // Failed attempts repeated before this result (see check_retries)
+	SoftError    string        // Soft error kind ("maintenance", "portal", "empty"): the page loaded but is not the monitored content
+	SoftDetail   string        // What the soft error was recognized by
+	Throttled    bool          // The site answered 429, or 503 with Retry-After: it asked to slow down, this is not an outage
+	RetryAfter   time.Duration // How long the site asked to wait (Retry-After, capped at retry_after_max_seconds)
 }
 
 // AlertKey uniquely identifies an alert type for a URL
@@ -82,6 +94,45 @@ type HTTPRateLimiter struct {
 	mu       sync.Mutex
 	limit    int
 	window   time.Duration
+	rejected atomic.Uint64 // Requests rejected since startup (for metrics)
+}
+
+// URLSettings are the settings in effect for one URL (see Config.urlSettings)
+type URLSettings struct {
+	CheckIntervalSeconds int
+	Schedule             string        // Schedule expression; empty = every CheckIntervalSeconds
+	schedule             checkSchedule // Parsed Schedule
+	ConnectTimeout       int
+	AlertCooldownMinutes int
+	MaxEmailsPerDay      int
+	Recipients           []string
+	Proxy                string          // Proxy URL, "direct", or empty for the proxy environment variables
+	Overridden           map[string]bool // URLConfig setting names that override the global value
+}
+
+// URLStatus is a point-in-time snapshot of a monitored URL's state
+type URLStatus struct {
+	ID          string // Stable short ID derived from the URL (see urlID)
+	URL         string
+	Name        string
+	SearchTerms []string
+	Found       bool
+	Unreachable bool
+	DownSince   time.Time // Zero if reachable
+	LastCheck   time.Time // Zero if not checked yet
+	NextCheck   time.Time // Zero if not checked yet
+	Checking    bool      // A check is currently running
+	Settings    URLSettings
+	Flapping    bool      // Reachability changes too often; error/recovery emails are suppressed
+	Failures    int       // Consecutive failed checks (the URL is unreachable from failure_threshold on)
+	SoftError   string    // Soft error kind of the last page received, empty if it was the real page
+	Throttled   time.Time // Checks are paused until then because the site asked to wait (zero if not)
+}
+
+// FailureStreak counts the consecutive failed checks of a URL
+type FailureStreak struct {
+	Count int
+	Since time.Time // When the first failed check of the streak ran
 }
 
 // IncidentInfo represents an incident for display
@@ -96,12 +147,29 @@ type IncidentInfo struct {
 
 // EmailNotification represents an email that was sent
 type EmailNotification struct {
-	Timestamp  time.Time
-	Recipients []string
-	URL        string
-	URLName    string
-	Type       string // "match", "error", "recovery"
-	Subject    string
+	Timestamp   time.Time
+	Recipients  []string // Configured recipients only
+	Subscribers int      // Self-service subscribers reached (their addresses are not kept)
+	URL         string
+	URLName     string
+	Type        string // "match", "error", "recovery"
+	Subject     string
+}
+
+// PageCacheEntry holds the validators and parsed result of the last page fetched for a URL
+type PageCacheEntry struct {
+	ETag         string         // ETag response header, sent back as If-None-Match
+	LastModified string         // Last-Modified response header, sent back as If-Modified-Since
+	BodyHash     [32]byte       // SHA-256 of the body, for servers without validators
+	SearchTerms  []string       // Search terms the result was computed with
+	Extractor    string         // Extractor the result was computed with
+	Result       URLCheckResult // Last fully parsed check
+}
+
+// urlTransport is a copy of the shared transport with one URL's TLS settings
+type urlTransport struct {
+	key       string // TLS settings it was built for
+	transport *http.Transport
 }
 
 // DNSCacheEntry holds cached DNS resolution with expiry
@@ -118,6 +186,83 @@ type DNSCache struct {
 	entries map[string]*DNSCacheEntry // key: hostname
 	mu      sync.RWMutex
 	ttl     time.Duration // How long to cache DNS entries
+
+	// Counters since startup (for metrics)
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	ipChanges atomic.Uint64
+}
+
+// CheckMetrics holds per-URL check counters and the duration histogram
+type CheckMetrics struct {
+	DurationBuckets []uint64 // Cumulative counts per metricsDurationBuckets entry
+	DurationSum     float64  // Seconds
+	DurationCount   uint64
+	StatusCodes     map[int]uint64    // HTTP responses by status code
+	Errors          uint64            // Checks that got no HTTP response (DNS, connect, timeout)
+	Unchanged       uint64            // Checks that reused the previous result (HTTP 304 or same body)
+	Retries         uint64            // Attempts repeated after a failure (check_retries)
+	SoftErrors      map[string]uint64 // Checks that got a maintenance, portal or empty page, by kind
+}
+
+// Metrics collects counters exposed on the Prometheus /metrics endpoint
+type Metrics struct {
+	checks       map[string]*CheckMetrics // key: URL
+	emailsSent   map[string]uint64        // key: email type
+	emailsFailed map[string]uint64        // key: email type
+	mu           sync.Mutex
+}
+
+// CheckRecord is one entry in a URL's check history
+type CheckRecord struct {
+	CheckedAt    time.Time
+	StatusCode   int
+	ResponseTime time.Duration
+	Found        bool
+	Date         string
+	Time         string
+	Address      string
+	Error        string
+	Manual       bool   // Triggered via "check now"
+	Unchanged    bool   // Page not modified since the previous check
+	SoftError    string // Soft error kind, empty for a real page
+	SoftDetail   string
+}
+
+// URLSnapshot is the last page body fetched for a URL
+type URLSnapshot struct {
+	FetchedAt   time.Time
+	StatusCode  int
+	Body        string   // Raw body (truncated to maxSnapshotBytes)
+	Text        string   // Text rendering of Body
+	MatchedRows []string // Table rows / text blocks that matched the search terms
+	Truncated   bool
+	Structure   PageStructure
+}
+
+// PageStructure records the page landmarks the extractors depend on
+type PageStructure struct {
+	Tables  int      // Number of <table> elements
+	Markers []string // Entries of structureMarkers present on the page
+}
+
+// DiffLine is one line of a text diff between two page versions
+type DiffLine struct {
+	Op   string // "same", "add", "del" or "skip" (collapsed unchanged lines)
+	Text string
+}
+
+// ServerEvent is a message published to dashboard clients over Server-Sent Events
+type ServerEvent struct {
+	Type string // SSE event name: "check", "state", "incident", "notification"
+	Data []byte // JSON payload
+}
+
+// EventBroker fans out ServerEvents to connected SSE clients
+type EventBroker struct {
+	clients    map[chan ServerEvent]struct{}
+	maxClients int
+	mu         sync.Mutex
 }
 
 // MatchRecord represents a seen match (for deduplication)
@@ -131,6 +276,23 @@ type MatchRecord struct {
 	URL          string    `json:"url"`
 }
 
+// Subscriber represents a confirmed self-service alert subscriber
+type Subscriber struct {
+	Email       string    `json:"email"`
+	Terms       []string  `json:"terms"`    // Area terms; empty means all alerts
+	Language    string    `json:"language"` // Preferred locale for alerts
+	CreatedAt   time.Time `json:"created_at"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+}
+
+// PendingSubscription is a subscription request awaiting email confirmation
+type PendingSubscription struct {
+	Email       string    `json:"email"`
+	Terms       []string  `json:"terms"`
+	Language    string    `json:"language"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
 // ServiceState represents the persistent state across restarts
 type ServiceState struct {
 	EmailsSentPerURLToday      map[string][]time.Time    `json:"emails_sent_per_url_today"`
@@ -138,6 +300,9 @@ type ServiceState struct {
 	LastAlertTimes             map[string]time.Time      `json:"last_alert_times"` // key: "url|alertType"
 	SeenMatches                map[string]*MatchRecord   `json:"seen_matches"`     // key: content hash
 	RecentEmailNotifications   []EmailNotification       `json:"recent_email_notifications"` // Recent email history
+	Subscribers                map[string]*Subscriber          `json:"subscribers"`           // key: lowercase email
+	PendingSubscriptions       map[string]*PendingSubscription `json:"pending_subscriptions"` // key: confirmation token
+	Unsubscribed               map[string]time.Time            `json:"unsubscribed"`          // Config recipients who opted out (key: lowercase email)
 	LastSaved                  time.Time                 `json:"last_saved"`
 	mu                         sync.RWMutex              `json:"-"`
 }
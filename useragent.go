@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -17,8 +20,23 @@ type UserAgentManager struct {
 	agents        []string
 	mu            sync.RWMutex
 	fallbackAgent string
+	fetchStatus   string    // "pending", "ok", "cached", "failed" or "disabled" (for health checks)
+	fetchError    string    // Last fetch error, if any
+	fetchedAt     time.Time // When the last fetch attempt finished
+	poolFetchedAt time.Time // When the User-Agents in use were fetched (zero while the fallback is used)
+	staleNotified bool      // A fetch failure email was sent since the last successful fetch
 }
 
+// userAgentCache is the on-disk copy of the last fetched User-Agent list (user_agent_cache_file)
+type userAgentCache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Source    string    `json:"source"`
+	Agents    []string  `json:"agents"`
+}
+
+// userAgentRetryInterval is how soon a failed User-Agent fetch is retried
+const userAgentRetryInterval = time.Hour
+
 // Default fallback User-Agent (current hardcoded one)
 const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"
 
@@ -36,80 +54,245 @@ func NewUserAgentManager() *UserAgentManager {
 	return &UserAgentManager{
 		agents:        []string{defaultUserAgent},
 		fallbackAgent: defaultUserAgent,
+		fetchStatus:   "pending",
 	}
 }
 
-// FetchUserAgents tries to fetch recent User-Agent strings from online sources
-func (uam *UserAgentManager) FetchUserAgents(config Config) error {
-	log.Printf("📡 Fetching recent User-Agent strings...")
+// userAgentSource returns where the User-Agent list is fetched from
+func userAgentSource(config Config) string {
+	if config.UserAgentSource != "" {
+		return config.UserAgentSource
+	}
+	return userAgentSourceURL
+}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+// isRemoteSource reports whether a User-Agent source is a URL rather than a local file
+func isRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// parseUserAgentList parses a User-Agent list: a JSON array of strings, or one User-Agent per line
+// (blank lines and lines starting with # are skipped)
+func parseUserAgentList(data []byte) ([]string, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var agents []string
+		if err := json.Unmarshal(data, &agents); err != nil {
+			return nil, fmt.Errorf("failed to parse User-Agent JSON: %w", err)
+		}
+		return agents, nil
 	}
 
-	var fetchedAgents []string
-	
-	log.Printf("   Fetching from: %s", userAgentSourceURL)
-	
-	resp, err := client.Get(userAgentSourceURL)
+	agents := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			agents = append(agents, line)
+		}
+	}
+	return agents, nil
+}
+
+// readUserAgentSource reads the User-Agent list from a URL (using the given client) or a local file
+func readUserAgentSource(source string, client *http.Client) ([]string, error) {
+	if !isRemoteSource(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read User-Agent file: %w", err)
+		}
+		return parseUserAgentList(data)
+	}
+
+	resp, err := client.Get(source)
 	if err != nil {
-		errMsg := fmt.Sprintf("Failed to fetch User-Agent strings from GitHub: %v", err)
-		log.Printf("⚠️  %s", errMsg)
-		// Send notification email
-		uam.sendFetchFailureEmail(config, errMsg)
-		return fmt.Errorf("failed to fetch User-Agent strings from GitHub: %w", err)
+		return nil, fmt.Errorf("failed to fetch User-Agent strings: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		errMsg := fmt.Sprintf("Failed to fetch User-Agent strings: HTTP %d", resp.StatusCode)
-		log.Printf("⚠️  %s", errMsg)
-		uam.sendFetchFailureEmail(config, errMsg)
-		return fmt.Errorf("failed to fetch User-Agent strings: HTTP %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to fetch User-Agent strings: HTTP %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		errMsg := fmt.Sprintf("Failed to read User-Agent response: %v", err)
-		log.Printf("⚠️  %s", errMsg)
-		uam.sendFetchFailureEmail(config, errMsg)
-		return fmt.Errorf("failed to read User-Agent response: %w", err)
+		return nil, fmt.Errorf("failed to read User-Agent response: %w", err)
 	}
+	return parseUserAgentList(body)
+}
 
-	// Parse JSON array of User-Agent strings
-	var jsonAgents []string
-	if err := json.Unmarshal(body, &jsonAgents); err != nil {
-		errMsg := fmt.Sprintf("Failed to parse User-Agent JSON: %v", err)
-		log.Printf("⚠️  %s", errMsg)
-		uam.sendFetchFailureEmail(config, errMsg)
-		return fmt.Errorf("failed to parse User-Agent JSON: %w", err)
-	}
+// FetchUserAgents fetches the User-Agent list from its source, selects the pool and caches the list to disk
+// On failure the pool in use (cached or fallback) is kept
+func (uam *UserAgentManager) FetchUserAgents(config Config, client *http.Client) error {
+	source := userAgentSource(config)
+	log.Printf("📡 Fetching recent User-Agent strings from %s", source)
 
-	fetchedAgents = jsonAgents
-	
+	fetchedAgents, err := readUserAgentSource(source, client)
+	if err != nil {
+		log.Printf("⚠️  %v", err)
+		return err
+	}
 	if len(fetchedAgents) == 0 {
-		errMsg := "No User-Agent strings found in response"
-		log.Printf("⚠️  %s", errMsg)
-		uam.sendFetchFailureEmail(config, errMsg)
-		return fmt.Errorf("no User-Agent strings found in response")
+		log.Printf("⚠️  No User-Agent strings found in %s", source)
+		return errors.New("no User-Agent strings found in response")
 	}
 
-	log.Printf("   ✅ Fetched %d User-Agent strings from microlinkhq/top-user-agents", len(fetchedAgents))
+	log.Printf("   ✅ Fetched %d User-Agent strings", len(fetchedAgents))
+	now := time.Now()
+	uam.usePool(config, fetchedAgents, now)
 
+	// Keep the full list, so a restart (or another pool size) works without fetching again
+	cache := userAgentCache{FetchedAt: now, Source: source, Agents: fetchedAgents}
+	if err := writeUserAgentCache(config.UserAgentCacheFile, cache); err != nil {
+		log.Printf("⚠️  Failed to cache User-Agent list to %s: %v", config.UserAgentCacheFile, err)
+	}
+	return nil
+}
+
+// usePool selects the rotation pool from a fetched User-Agent list
+func (uam *UserAgentManager) usePool(config Config, fetchedAgents []string, fetchedAt time.Time) {
 	// Select N diverse ones (prefer recent Chrome, Firefox, Safari)
 	poolSize := config.UserAgentPoolSize
 	if poolSize < 1 {
 		poolSize = 6 // Default
 	}
 	selectedAgents := uam.selectUserAgents(fetchedAgents, poolSize)
-	
+
 	uam.mu.Lock()
 	uam.agents = selectedAgents
+	uam.poolFetchedAt = fetchedAt
 	uam.mu.Unlock()
 
 	log.Printf("✅ User-Agent pool ready with %d agents", len(selectedAgents))
-	
-	return nil
+}
+
+// LoadCache starts with the User-Agent list cached by a previous run, returning when it was fetched
+func (uam *UserAgentManager) LoadCache(config Config) (time.Time, error) {
+	data, err := os.ReadFile(config.UserAgentCacheFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var cache userAgentCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %s: %w", config.UserAgentCacheFile, err)
+	}
+	if len(cache.Agents) == 0 {
+		return time.Time{}, fmt.Errorf("%s contains no User-Agent strings", config.UserAgentCacheFile)
+	}
+
+	log.Printf("📂 Loaded %d cached User-Agent strings from %s (fetched %s)", len(cache.Agents), config.UserAgentCacheFile, cache.FetchedAt.Format("2006-01-02 15:04"))
+	uam.usePool(config, cache.Agents, cache.FetchedAt)
+
+	uam.mu.Lock()
+	uam.fetchStatus = "cached"
+	uam.mu.Unlock()
+	return cache.FetchedAt, nil
+}
+
+// writeUserAgentCache saves the fetched User-Agent list (temporary file + rename, like the state file)
+func writeUserAgentCache(path string, cache userAgentCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// refreshUserAgents starts from the cached User-Agent list and fetches a new one every
+// user_agent_refresh_hours (hourly after a failure); it runs until shutdown
+// The fetch goes through the global proxy, like the checks
+func (m *Monitor) refreshUserAgents() {
+	uam := m.userAgentManager
+	config := m.getConfig()
+	refresh := time.Duration(config.UserAgentRefreshHours) * time.Hour
+
+	next := time.Now()
+	if fetchedAt, err := uam.LoadCache(*config); err == nil {
+		next = fetchedAt.Add(refresh)
+	} else if !os.IsNotExist(err) {
+		log.Printf("⚠️  Ignoring User-Agent cache: %v", err)
+	}
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-m.stopChan:
+			return
+		}
+
+		config := m.getConfig()
+		client := &http.Client{Transport: m.roundTripper(m.httpTransport, config.ProxyURL), Timeout: 10 * time.Second}
+		err := uam.FetchUserAgents(*config, client)
+		uam.recordFetchResult(err)
+		if err == nil {
+			timer.Reset(time.Duration(config.UserAgentRefreshHours) * time.Hour)
+			continue
+		}
+
+		if _, _, _, poolFetchedAt := uam.GetFetchStatus(); poolFetchedAt.IsZero() {
+			log.Printf("⚠️  Using fallback User-Agent due to fetch failure")
+		} else {
+			log.Printf("⚠️  Keeping cached User-Agents fetched %s", poolFetchedAt.Format("2006-01-02 15:04"))
+		}
+		if uam.shouldNotifyFailure(time.Duration(config.UserAgentStaleDays) * 24 * time.Hour) {
+			uam.sendFetchFailureEmail(*config, err.Error())
+		}
+		timer.Reset(userAgentRetryInterval)
+	}
+}
+
+// recordFetchResult records the outcome of a fetch attempt for health reporting
+// A failed fetch leaves the status "cached" while a previously fetched pool is in use
+func (uam *UserAgentManager) recordFetchResult(err error) {
+	uam.mu.Lock()
+	defer uam.mu.Unlock()
+
+	uam.fetchedAt = time.Now()
+	if err != nil {
+		uam.fetchStatus = "failed"
+		if !uam.poolFetchedAt.IsZero() {
+			uam.fetchStatus = "cached"
+		}
+		uam.fetchError = err.Error()
+	} else {
+		uam.fetchStatus = "ok"
+		uam.fetchError = ""
+		uam.staleNotified = false
+	}
+}
+
+// setRotationDisabled marks the fetch as intentionally skipped
+func (uam *UserAgentManager) setRotationDisabled() {
+	uam.mu.Lock()
+	defer uam.mu.Unlock()
+	uam.fetchStatus = "disabled"
+}
+
+// GetFetchStatus returns the fetch status, last error, when the last attempt finished
+// and when the pool in use was fetched (zero for the fallback)
+func (uam *UserAgentManager) GetFetchStatus() (string, string, time.Time, time.Time) {
+	uam.mu.RLock()
+	defer uam.mu.RUnlock()
+	return uam.fetchStatus, uam.fetchError, uam.fetchedAt, uam.poolFetchedAt
+}
+
+// shouldNotifyFailure reports whether a failed fetch warrants an email: the pool in use is the
+// fallback or older than maxAge, and no email was sent since the last successful fetch
+func (uam *UserAgentManager) shouldNotifyFailure(maxAge time.Duration) bool {
+	uam.mu.Lock()
+	defer uam.mu.Unlock()
+
+	stale := uam.poolFetchedAt.IsZero() || time.Since(uam.poolFetchedAt) > maxAge
+	if !stale || uam.staleNotified {
+		return false
+	}
+	uam.staleNotified = true
+	return true
 }
 
 // selectUserAgents picks N User-Agent strings (prefer recent Chrome, Firefox, Safari)
@@ -188,39 +371,43 @@ func (uam *UserAgentManager) GetNext() string {
 	return uam.agents[randomIndex]
 }
 
-// sendFetchFailureEmail notifies admin about User-Agent fetch failure
+// sendFetchFailureEmail notifies admin that the User-Agent list could not be fetched and the pool in use is stale
 func (uam *UserAgentManager) sendFetchFailureEmail(config Config, errorMsg string) {
 	if config.ErrorRecipient == "" {
 		return
 	}
 
+	uam.mu.RLock()
+	poolFetchedAt := uam.poolFetchedAt
+	uam.mu.RUnlock()
+
+	inUse := fmt.Sprintf("the hardcoded fallback User-Agent:\n%s", defaultUserAgent)
+	if !poolFetchedAt.IsZero() {
+		inUse = fmt.Sprintf("the cached User-Agent list fetched %s (%s)", poolFetchedAt.Format("2006-01-02 15:04"), config.UserAgentCacheFile)
+	}
+
 	subject := "⚠️ Nestanak-Info - User-Agent Fetch Failed"
 	body := fmt.Sprintf(`User-Agent Fetch Failure
 
-The service failed to fetch recent User-Agent strings from GitHub and is using the hardcoded fallback.
+The service failed to fetch recent User-Agent strings and is using %s
 
 Error Details:
 %s
 
 Source:
 %s
-(microlinkhq/top-user-agents - Top 100 HTTP User-Agents from 300M+ monthly requests)
-
-Fallback User-Agent:
-%s
 
 Impact:
 - Service continues operating normally
-- Using static User-Agent (may be less effective at avoiding detection)
+- The User-Agents in use are getting old (may be less effective at avoiding detection)
 
 Timestamp: %s
 
-The service will retry fetching on next restart.
-
-Repository: https://github.com/microlinkhq/top-user-agents`, 
+The service retries every hour; this email is not repeated until a fetch succeeds.
+Set user_agent_source to a local file to rotate User-Agents without network access.`,
+		inUse,
 		errorMsg,
-		userAgentSourceURL,
-		defaultUserAgent,
+		userAgentSource(config),
 		time.Now().Format("2006-01-02 15:04:05"))
 
 	if err := sendBrevoEmail(config, config.ErrorRecipient, subject, body); err != nil {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
@@ -325,3 +327,8 @@ func (csm *CachedStatsManager) GetOrCreate(key string) *CachedStats {
 	return cs
 }
 
+// urlID returns a short stable identifier for a monitored URL (used in web and API paths)
+func urlID(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:6])
+}